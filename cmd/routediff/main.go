@@ -0,0 +1,118 @@
+// Command routediff compares two JSON snapshots of route listings and reports
+// the changes between them.
+//
+// A snapshot is the JSON encoding of the result of [route.Route.Routes]. The
+// command exits with status 1 if there are breaking changes that are not
+// listed in the approval file, one [route.Change] per line as printed by this
+// command. Empty lines and lines starting with # are ignored.
+//
+// Removed middleware is only detected for middleware named with
+// [route.Named], as the names derived from anonymous functions change with
+// unrelated code edits.
+//
+// Usage:
+//
+//	routediff [-approved file] old.json new.json
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sehrgutesoftware/goweb/route"
+)
+
+func main() {
+	approvedFile := flag.String("approved", "", "file listing approved breaking changes")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-approved file] old.json new.json\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	breaking, err := run(flag.Arg(0), flag.Arg(1), *approvedFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if breaking > 0 {
+		fmt.Fprintf(os.Stderr, "%d unapproved breaking change(s)\n", breaking)
+		os.Exit(1)
+	}
+}
+
+// run prints the changes between the snapshots and returns the number of
+// unapproved breaking changes.
+func run(oldFile, newFile, approvedFile string) (int, error) {
+	oldRoutes, err := readSnapshot(oldFile)
+	if err != nil {
+		return 0, err
+	}
+	newRoutes, err := readSnapshot(newFile)
+	if err != nil {
+		return 0, err
+	}
+
+	var approved []string
+	if approvedFile != "" {
+		approved, err = readApproved(approvedFile)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	changes := route.Diff(oldRoutes, newRoutes)
+	unapproved := route.Breaking(changes, approved)
+	for _, c := range changes {
+		marker := " "
+		if c.Breaking {
+			marker = "!"
+		}
+		fmt.Printf("%s %s\n", marker, c)
+	}
+
+	return len(unapproved), nil
+}
+
+// readSnapshot reads a JSON snapshot of a route listing.
+func readSnapshot(name string) ([]route.Info, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []route.Info
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return routes, nil
+}
+
+// readApproved reads the list of approved changes.
+func readApproved(name string) ([]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var approved []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		approved = append(approved, line)
+	}
+	return approved, scanner.Err()
+}
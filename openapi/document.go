@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an OpenAPI 3.1 document.
+type Document struct {
+	OpenAPI    string              `json:"openapi" yaml:"openapi"`
+	Info       Info                `json:"info" yaml:"info"`
+	Servers    []Server            `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths" yaml:"paths"`
+	Components *Components         `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+// JSON returns the document serialized as indented JSON.
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML returns the document serialized as YAML.
+func (d *Document) YAML() ([]byte, error) {
+	return yaml.Marshal(d)
+}
+
+// Info is the metadata of the API described by a [Document].
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Version     string `json:"version" yaml:"version"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Server is a server the API described by a [Document] is served by.
+type Server struct {
+	URL       string                    `json:"url" yaml:"url"`
+	Variables map[string]ServerVariable `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// ServerVariable is a variable of a [Server] URL, such as a host parameter.
+type ServerVariable struct {
+	Default string `json:"default" yaml:"default"`
+}
+
+// PathItem maps lowercase HTTP methods to the operations of a path.
+type PathItem map[string]*OperationObject
+
+// OperationObject describes a single API operation on a path.
+type OperationObject struct {
+	OperationID string               `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Summary     string               `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses" yaml:"responses"`
+	Servers     []Server             `json:"servers,omitempty" yaml:"servers,omitempty"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name     string  `json:"name" yaml:"name"`
+	In       string  `json:"in" yaml:"in"`
+	Required bool    `json:"required" yaml:"required"`
+	Schema   *Schema `json:"schema" yaml:"schema"`
+}
+
+// RequestBody describes the request body of an operation.
+type RequestBody struct {
+	Required bool                  `json:"required" yaml:"required"`
+	Content  map[string]*MediaType `json:"content" yaml:"content"`
+}
+
+// Response describes a single response of an operation.
+type Response struct {
+	Description string                `json:"description" yaml:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+// MediaType describes the schema of a request or response body.
+type MediaType struct {
+	Schema *Schema `json:"schema" yaml:"schema"`
+}
+
+// Components holds the reusable schemas referenced from the document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty" yaml:"schemas,omitempty"`
+}
+
+// Schema is a JSON Schema (draft 2020-12) as used by OpenAPI 3.1.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty" yaml:"type,omitempty"`
+	Format               string             `json:"format,omitempty" yaml:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty" yaml:"enum,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty" yaml:"oneOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	MinLength            *uint64            `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *uint64            `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	MinItems             *uint64            `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *uint64            `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	MinProperties        *uint64            `json:"minProperties,omitempty" yaml:"minProperties,omitempty"`
+	MaxProperties        *uint64            `json:"maxProperties,omitempty" yaml:"maxProperties,omitempty"`
+}
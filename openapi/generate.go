@@ -0,0 +1,402 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sehrgutesoftware/goweb"
+	"github.com/sehrgutesoftware/goweb/route"
+)
+
+// metaKey is the [route.Route] metadata key under which the [Operation] of a
+// route is stored.
+const metaKey = "openapi.operation"
+
+// Operation documents a route for the generated OpenAPI document.
+type Operation struct {
+	// ID is the operationId. It defaults to the name of the route.
+	ID string
+	// Summary is a short summary of the operation.
+	Summary string
+	// Description is a verbose explanation of the operation.
+	Description string
+	// Tags group operations in the generated documentation.
+	Tags []string
+	// Deprecated marks the operation as deprecated.
+	Deprecated bool
+	// Request is a value of the type of the JSON request body, if any.
+	Request any
+	// Response is a value of the type of the JSON response body, if any.
+	Response any
+	// Status is the HTTP status of a successful response. Defaults to 200.
+	Status int
+	// Errors are the errors the operation can respond with.
+	Errors []goweb.APIError
+}
+
+// Describe attaches the OpenAPI operation to the route.
+func Describe(r *route.Route, op Operation) *route.Route {
+	return r.Meta(metaKey, op)
+}
+
+// Generate creates an OpenAPI 3.1 document for the given route tree.
+//
+// Path parameters are taken from the route paths, request and response bodies
+// from the [Operation] attached to the route using [Describe]. Constraints are
+// derived from the `validate` struct tags of the body types.
+//
+// Operations of [route.Host] groups list their host as server. If several
+// hosts serve the same method and path, only the first route is documented;
+// use [GenerateHost] to document each host on its own.
+func Generate(r *route.Route, info Info) (*Document, error) {
+	routes, err := r.Routes()
+	if err != nil {
+		return nil, err
+	}
+	return generate(routes, info, true)
+}
+
+// GenerateHost creates an OpenAPI 3.1 document for the routes of the tree
+// served on the given host pattern, as passed to [route.Host]. An empty host
+// selects the routes without a host. See [Generate].
+func GenerateHost(r *route.Route, info Info, host string) (*Document, error) {
+	routes, err := r.Routes()
+	if err != nil {
+		return nil, err
+	}
+	routes = slices.DeleteFunc(routes, func(ri route.Info) bool { return ri.Host != host })
+
+	doc, err := generate(routes, info, false)
+	if err != nil {
+		return nil, err
+	}
+	if host != "" {
+		doc.Servers = []Server{hostServer(host)}
+	}
+	return doc, nil
+}
+
+// generate creates the document for the routes. If hostServers is set, the
+// operations of routes with a host list it as their server.
+func generate(routes []route.Info, info Info, hostServers bool) (*Document, error) {
+	g := generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+
+	doc := Document{
+		OpenAPI: "3.1.0",
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+
+	// Routes with predicates share their method and path with the other
+	// candidates for the request.
+	type location struct{ host, method, path string }
+	var locations []location
+	candidates := make(map[location][]route.Info)
+	for _, ri := range routes {
+		if !slices.Contains(operationMethods, ri.Method) {
+			continue
+		}
+		loc := location{ri.Host, ri.Method, ri.Path}
+		if _, ok := candidates[loc]; !ok {
+			locations = append(locations, loc)
+		}
+		candidates[loc] = append(candidates[loc], ri)
+	}
+
+	// hosts are the hosts of the documented operations by method and path.
+	hosts := make(map[string]string)
+	for _, loc := range locations {
+		group := candidates[loc]
+		path := convertPath(loc.path)
+		method := strings.ToLower(loc.method)
+
+		key := method + " " + path
+		host, ok := hosts[key]
+		if ok && host != loc.host {
+			// Another host serves the same operation
+			continue
+		}
+		if ok || (len(group) > 1 && !slices.ContainsFunc(group, hasPredicates)) {
+			return nil, fmt.Errorf("duplicate operation %s %s", loc.method, loc.path)
+		}
+		hosts[key] = loc.host
+
+		op := g.operation(group)
+		if hostServers && loc.host != "" {
+			op.Servers = []Server{hostServer(loc.host)}
+		}
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][method] = op
+	}
+
+	if len(g.schemas) > 0 {
+		doc.Components = &Components{Schemas: g.schemas}
+	}
+
+	return &doc, nil
+}
+
+// operationMethods are the methods a path item can have operations for. Other
+// methods, like the CONNECT method of [route.Mount], are not documented.
+var operationMethods = []string{
+	http.MethodGet,
+	http.MethodPut,
+	http.MethodPost,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodHead,
+	http.MethodPatch,
+	http.MethodTrace,
+}
+
+// hostServer returns the server for a host pattern, with a variable for each
+// of its parameters.
+func hostServer(host string) Server {
+	server := Server{URL: "https://" + host}
+	for _, label := range strings.Split(host, ".") {
+		if strings.HasPrefix(label, "{") && strings.HasSuffix(label, "}") {
+			name := label[1 : len(label)-1]
+			if server.Variables == nil {
+				server.Variables = make(map[string]ServerVariable)
+			}
+			server.Variables[name] = ServerVariable{Default: name}
+		}
+	}
+	return server
+}
+
+// generator holds the state of a single [Generate] call.
+type generator struct {
+	// schemas are the named schemas collected for the components section.
+	schemas map[string]*Schema
+	// names maps Go types to their component schema names.
+	names map[reflect.Type]string
+}
+
+// operation creates the OpenAPI operation for the routes sharing a method and
+// path. Several routes are candidates chosen by their predicates, see
+// [route.Route.When]; their bodies are merged using oneOf.
+func (g *generator) operation(candidates []route.Info) *OperationObject {
+	ri := candidates[0]
+	op, _ := ri.Meta[metaKey].(Operation)
+
+	obj := OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+		Responses:   make(map[string]*Response),
+	}
+	if obj.OperationID == "" {
+		obj.OperationID = ri.Name
+	}
+	if obj.Description == "" {
+		obj.Description = ri.Description
+	}
+
+	for _, segment := range strings.Split(ri.Path, "/") {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			continue
+		}
+		name, constraint, _ := strings.Cut(segment[1:], "|")
+		obj.Parameters = append(obj.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   constraintSchema(constraint),
+		})
+	}
+
+	var errs []goweb.APIError
+	requests := make(map[string][]reflect.Type)
+	responses := make(map[int][]reflect.Type)
+	for _, c := range candidates {
+		op, _ := c.Meta[metaKey].(Operation)
+
+		if op.Request != nil {
+			t := reflect.TypeOf(op.Request)
+			for _, mediaType := range requestTypes(c) {
+				requests[mediaType] = appendType(requests[mediaType], t)
+			}
+			if hasValidation(t) {
+				errs = append(errs, errInvalidEntity)
+			}
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if op.Response != nil {
+			responses[status] = appendType(responses[status], reflect.TypeOf(op.Response))
+		} else if _, ok := responses[status]; !ok {
+			responses[status] = nil
+		}
+
+		errs = append(errs, op.Errors...)
+	}
+	errs = append(errs, predicateErrors(candidates)...)
+
+	if len(requests) > 0 {
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content:  make(map[string]*MediaType, len(requests)),
+		}
+		for mediaType, types := range requests {
+			obj.RequestBody.Content[mediaType] = &MediaType{Schema: g.oneOf(types)}
+		}
+	}
+
+	for status, types := range responses {
+		success := Response{Description: http.StatusText(status)}
+		if len(types) > 0 {
+			success.Content = map[string]*MediaType{
+				"application/json": {Schema: g.oneOf(types)},
+			}
+		}
+		obj.Responses[strconv.Itoa(status)] = &success
+	}
+
+	for status, codes := range errorCodes(errs) {
+		obj.Responses[strconv.Itoa(status)] = &Response{
+			Description: http.StatusText(status),
+			Content: map[string]*MediaType{
+				"application/json": {Schema: errorSchema(codes)},
+			},
+		}
+	}
+
+	return &obj
+}
+
+// oneOf returns the schema of a body having one of the types.
+func (g *generator) oneOf(types []reflect.Type) *Schema {
+	if len(types) == 1 {
+		return g.schema(types[0])
+	}
+
+	schema := Schema{OneOf: make([]*Schema, 0, len(types))}
+	for _, t := range types {
+		schema.OneOf = append(schema.OneOf, g.schema(t))
+	}
+	return &schema
+}
+
+// appendType appends the type to the list, unless it is contained already.
+func appendType(types []reflect.Type, t reflect.Type) []reflect.Type {
+	if slices.Contains(types, t) {
+		return types
+	}
+	return append(types, t)
+}
+
+// contentTypePrefix is the prefix of the description of a
+// [route.ContentType] predicate.
+const contentTypePrefix = "content-type "
+
+// hasPredicates reports whether the route has predicates.
+func hasPredicates(ri route.Info) bool {
+	return len(ri.Predicates) > 0
+}
+
+// requestTypes returns the media types of the request body of a route, as
+// restricted by a [route.ContentType] predicate. JSON is the default.
+func requestTypes(ri route.Info) []string {
+	for _, p := range ri.Predicates {
+		if types, ok := strings.CutPrefix(p, contentTypePrefix); ok {
+			return strings.Split(types, ",")
+		}
+	}
+	return []string{"application/json"}
+}
+
+// predicateErrors returns the errors sent when a request fulfils the
+// predicates of none of the candidates. A candidate without predicates
+// matches every request.
+func predicateErrors(candidates []route.Info) []goweb.APIError {
+	if !hasPredicates(candidates[len(candidates)-1]) {
+		return nil
+	}
+
+	var errs []goweb.APIError
+	for _, c := range candidates {
+		for _, p := range c.Predicates {
+			if strings.HasPrefix(p, contentTypePrefix) {
+				errs = append(errs, route.ErrUnsupportedMediaType)
+			} else {
+				errs = append(errs, route.ErrNotAcceptable)
+			}
+		}
+	}
+	return errs
+}
+
+// errInvalidEntity mirrors the error returned by validators of the validate
+// package when validation fails.
+var errInvalidEntity = goweb.NewError("invalid_entity", "entity validation failed", http.StatusUnprocessableEntity)
+
+// errorCodes groups the codes of the given errors by HTTP status.
+func errorCodes(errs []goweb.APIError) map[int][]any {
+	codes := make(map[int][]any)
+	for _, err := range errs {
+		status := err.StatusCode()
+		if !slices.Contains(codes[status], any(err.ErrorCode())) {
+			codes[status] = append(codes[status], err.ErrorCode())
+		}
+	}
+	return codes
+}
+
+// errorSchema returns the schema of a [goweb.RespondError] response body with
+// one of the given error codes.
+func errorSchema(codes []any) *Schema {
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "message", "detail"},
+		Properties: map[string]*Schema{
+			"code":    {Type: "string", Enum: codes},
+			"message": {Type: "string"},
+			"detail":  {},
+		},
+	}
+}
+
+// convertPath converts an httprouter path pattern into an OpenAPI path
+// template.
+func convertPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment != "" && (segment[0] == ':' || segment[0] == '*') {
+			name, _, _ := strings.Cut(segment[1:], "|")
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// constraintSchema returns the schema of a path parameter with the given
+// inline constraint.
+func constraintSchema(constraint string) *Schema {
+	switch constraint {
+	case "int":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "uint":
+		var zero float64
+		return &Schema{Type: "integer", Format: "int64", Minimum: &zero}
+	case "float":
+		return &Schema{Type: "number", Format: "double"}
+	case "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	}
+	return &Schema{Type: "string"}
+}
@@ -0,0 +1,156 @@
+package openapi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sehrgutesoftware/goweb"
+	"github.com/sehrgutesoftware/goweb/openapi"
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type createUser struct {
+	Name string `json:"name" validate:"required,between:1:64"`
+	Age  int    `json:"age,omitempty" validate:"between:0:150"`
+}
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func noop(w http.ResponseWriter, r *http.Request) {}
+
+func TestItGeneratesAnOpenAPIDocument(t *testing.T) {
+	errConflict := goweb.NewError("user_exists", "user exists", http.StatusConflict)
+
+	tree := route.Group("/users", []*route.Route{
+		openapi.Describe(route.Func("POST", "", noop).Name("user.create"), openapi.Operation{
+			Request:  createUser{},
+			Response: user{},
+			Status:   http.StatusCreated,
+			Errors:   []goweb.APIError{errConflict},
+		}),
+		route.Func("GET", "/:id", noop),
+	})
+
+	doc, err := openapi.Generate(tree, openapi.Info{Title: "Test", Version: "1.0.0"})
+	assert.NoError(t, err)
+
+	create := doc.Paths["/users"]["post"]
+	assert.Equal(t, "user.create", create.OperationID)
+	assert.Contains(t, create.Responses, "201")
+	assert.Contains(t, create.Responses, "409")
+	assert.Contains(t, create.Responses, "422")
+
+	body := create.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/createUser", body.Ref)
+
+	schema := doc.Components.Schemas["createUser"]
+	assert.Equal(t, []string{"name"}, schema.Required)
+	assert.EqualValues(t, 1, *schema.Properties["name"].MinLength)
+	assert.EqualValues(t, 64, *schema.Properties["name"].MaxLength)
+	assert.EqualValues(t, 150, *schema.Properties["age"].Maximum)
+
+	show := doc.Paths["/users/{id}"]["get"]
+	assert.Equal(t, []openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}}}, show.Parameters)
+
+	_, err = doc.YAML()
+	assert.NoError(t, err)
+	_, err = doc.JSON()
+	assert.NoError(t, err)
+}
+
+func TestItGeneratesDocumentsForHosts(t *testing.T) {
+	tree := route.Group("/", []*route.Route{
+		route.Host("admin.example.com", []*route.Route{
+			route.Func("GET", "/users", noop).Name("admin.users"),
+		}),
+		route.Host("{tenant}.example.com", []*route.Route{
+			route.Func("GET", "/settings", noop),
+		}),
+		route.Func("GET", "/users", noop).Name("users"),
+	})
+	info := openapi.Info{Title: "Test", Version: "1.0.0"}
+
+	doc, err := openapi.Generate(tree, info)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin.users", doc.Paths["/users"]["get"].OperationID)
+	assert.Equal(t, []openapi.Server{{URL: "https://admin.example.com"}}, doc.Paths["/users"]["get"].Servers)
+	assert.Equal(t, []openapi.Server{{
+		URL:       "https://{tenant}.example.com",
+		Variables: map[string]openapi.ServerVariable{"tenant": {Default: "tenant"}},
+	}}, doc.Paths["/settings"]["get"].Servers)
+
+	doc, err = openapi.GenerateHost(tree, info, "")
+	assert.NoError(t, err)
+	assert.Empty(t, doc.Servers)
+	assert.Len(t, doc.Paths, 1)
+	assert.Equal(t, "users", doc.Paths["/users"]["get"].OperationID)
+	assert.Empty(t, doc.Paths["/users"]["get"].Servers)
+
+	doc, err = openapi.GenerateHost(tree, info, "admin.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []openapi.Server{{URL: "https://admin.example.com"}}, doc.Servers)
+	assert.Equal(t, "admin.users", doc.Paths["/users"]["get"].OperationID)
+}
+
+type upload struct {
+	URL string `json:"url"`
+}
+
+func TestItMergesRoutesChosenByPredicates(t *testing.T) {
+	tree := route.Group("/files", []*route.Route{
+		openapi.Describe(route.Func("POST", "", noop).Name("file.create"), openapi.Operation{
+			Request:  upload{},
+			Response: user{},
+		}).When(route.ContentType("application/json")),
+		openapi.Describe(route.Func("POST", "", noop), openapi.Operation{
+			Request:  createUser{},
+			Response: upload{},
+		}).When(route.ContentType("application/json", "application/x-www-form-urlencoded")),
+		route.Func("GET", "", noop).When(route.Header("X-Api-Version", "2")),
+		route.Func("GET", "", noop),
+		route.Func("DELETE", "", noop).When(route.Header("X-Confirm", "")),
+	})
+
+	doc, err := openapi.Generate(tree, openapi.Info{Title: "Test", Version: "1.0.0"})
+	assert.NoError(t, err)
+
+	create := doc.Paths["/files"]["post"]
+	assert.Equal(t, "file.create", create.OperationID)
+	assert.Equal(t, []*openapi.Schema{
+		{Ref: "#/components/schemas/upload"},
+		{Ref: "#/components/schemas/createUser"},
+	}, create.RequestBody.Content["application/json"].Schema.OneOf)
+	assert.Equal(t, "#/components/schemas/createUser", create.RequestBody.Content["application/x-www-form-urlencoded"].Schema.Ref)
+	assert.Len(t, create.Responses["200"].Content["application/json"].Schema.OneOf, 2)
+	assert.Contains(t, create.Responses, "415")
+	assert.NotContains(t, create.Responses, "406")
+
+	// The route without predicates serves all other requests
+	list := doc.Paths["/files"]["get"]
+	assert.NotContains(t, list.Responses, "406")
+	assert.Contains(t, doc.Paths["/files"]["delete"].Responses, "406")
+
+	_, err = openapi.Generate(route.Group("/", []*route.Route{
+		route.Func("GET", "/x", noop),
+		route.Func("GET", "/x", noop),
+	}), openapi.Info{})
+	assert.ErrorContains(t, err, "duplicate operation GET /x")
+}
+
+func TestItLeavesOutMethodsWithoutOperations(t *testing.T) {
+	tree := route.Group("/", []*route.Route{
+		route.Mount("/legacy", http.HandlerFunc(noop)),
+	})
+
+	doc, err := openapi.Generate(tree, openapi.Info{Title: "Test", Version: "1.0.0"})
+	assert.NoError(t, err)
+
+	item := doc.Paths["/legacy/{mountpath}"]
+	assert.Len(t, item, 8)
+	assert.Contains(t, item, "get")
+	assert.NotContains(t, item, "connect")
+}
@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+)
+
+// schema returns the JSON schema of the given type. Named struct types are
+// added to the components and referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() != reflect.Pointer && t.Implements(marshalerType):
+		// The JSON representation of the type is unknown.
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if typ, ok := s.Type.(string); ok {
+			s.Type = []string{typ, "null"}
+		}
+		return s
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return g.ref(t)
+	}
+
+	return &Schema{}
+}
+
+// ref returns a reference to the component schema of a named struct type,
+// generating the component if necessary.
+func (g *generator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		for i := 2; g.schemas[name] != nil; i++ {
+			name = fmt.Sprintf("%s%d", t.Name(), i)
+		}
+		g.names[t] = name
+
+		// Register a placeholder first so recursive types terminate.
+		g.schemas[name] = &Schema{}
+		*g.schemas[name] = *g.object(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object returns the object schema of a struct type.
+func (g *generator) object(t reflect.Type) *Schema {
+	s := Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		// Fields of embedded structs are promoted, like encoding/json does.
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := g.object(field.Type)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fs := g.schema(field.Type)
+		if required := applyValidation(fs, field); required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+
+	return &s
+}
+
+// jsonName returns the name of a struct field in its JSON representation and
+// whether the field is skipped entirely.
+func jsonName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	return name, false
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// applyValidation adds the constraints from the `validate` tag of the field to
+// its schema. It reports whether the field is required.
+func applyValidation(s *Schema, field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup("validate")
+	if !ok || tag == "" {
+		return false
+	}
+
+	typ := field.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	var required bool
+	for spec := range strings.SplitSeq(tag, ",") {
+		name, args, _ := strings.Cut(spec, ":")
+		switch name {
+		case "required":
+			required = true
+		case "between":
+			applyBetween(s, typ, args)
+		}
+	}
+	return required
+}
+
+// applyBetween adds the bounds of a `between` assertion to the schema.
+func applyBetween(s *Schema, typ reflect.Type, args string) {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		var lower, upper float64
+		if _, err := fmt.Sscanf(args, "%g:%g", &lower, &upper); err == nil {
+			s.Minimum, s.Maximum = &lower, &upper
+		}
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		var lower, upper uint64
+		if _, err := fmt.Sscanf(args, "%d:%d", &lower, &upper); err != nil {
+			return
+		}
+		switch typ.Kind() {
+		case reflect.String:
+			s.MinLength, s.MaxLength = &lower, &upper
+		case reflect.Map:
+			s.MinProperties, s.MaxProperties = &lower, &upper
+		default:
+			s.MinItems, s.MaxItems = &lower, &upper
+		}
+	}
+}
+
+// hasValidation reports whether the type is a struct with `validate` tags on
+// any of its fields, including nested structs.
+func hasValidation(t reflect.Type) bool {
+	return hasValidationSeen(t, make(map[reflect.Type]bool))
+}
+
+// hasValidationSeen implements hasValidation, guarding against recursion.
+func hasValidationSeen(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if tag, ok := field.Tag.Lookup("validate"); ok && tag != "" {
+			return true
+		}
+		if hasValidationSeen(field.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// ErrNoAuthorizer is returned by [Route.Build] if a route has authorization
+// requirements, but no [Authorizer] is configured with [WithAuthorizer].
+var ErrNoAuthorizer = errors.New("route requires authorization, but no authorizer is configured")
+
+// Principal is the authenticated caller of a request.
+type Principal interface {
+	// HasScope reports whether the caller was granted the given scope.
+	HasScope(scope string) bool
+	// HasRole reports whether the caller has the given role.
+	HasRole(role string) bool
+}
+
+// Authorizer resolves the [Principal] of a request from its context. It
+// reports false if the request is not authenticated.
+//
+// Authentication itself is usually done by a middleware, which stores the
+// caller in the request context for the Authorizer to pick up.
+type Authorizer interface {
+	Principal(ctx context.Context) (Principal, bool)
+}
+
+// AuthorizerFunc wraps a function to satisfy the [Authorizer] interface.
+type AuthorizerFunc func(ctx context.Context) (Principal, bool)
+
+// Principal returns the principal of the request.
+func (f AuthorizerFunc) Principal(ctx context.Context) (Principal, bool) {
+	return f(ctx)
+}
+
+// Require restricts the route and its children to callers that were granted
+// all of the given scopes. Scopes are inherited by the route's children,
+// which can require additional scopes.
+//
+// Unauthenticated requests are rejected with [ErrUnauthenticated], callers
+// lacking a scope with [ErrForbidden]. See [WithAuthorizer].
+func (r *Route) Require(scopes ...string) *Route {
+	r.scopes = append(r.scopes, scopes...)
+	return r
+}
+
+// Roles restricts the route and its children to callers that have at least
+// one of the given roles. Unlike scopes, the roles of a route replace the
+// roles inherited from its ancestors.
+//
+// Unauthenticated requests are rejected with [ErrUnauthenticated], callers
+// lacking all of the roles with [ErrForbidden]. See [WithAuthorizer].
+func (r *Route) Roles(roles ...string) *Route {
+	r.roles = append(r.roles, roles...)
+	return r
+}
+
+// Public reports whether the route can be called without authorization.
+func (i Info) Public() bool {
+	return len(i.Scopes) == 0 && len(i.Roles) == 0
+}
+
+// authorize rejects requests whose principal lacks any of the scopes or all
+// of the roles.
+func authorize(a Authorizer, scopes, roles []string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := a.Principal(r.Context())
+		if !ok {
+			_ = goweb.RespondError(w, r, ErrUnauthenticated)
+			return
+		}
+
+		if !allowed(p, scopes, roles) {
+			_ = goweb.RespondError(w, r, ErrForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowed reports whether the principal has all of the scopes and, if there
+// are any roles, one of them.
+func allowed(p Principal, scopes, roles []string) bool {
+	for _, scope := range scopes {
+		if !p.HasScope(scope) {
+			return false
+		}
+	}
+	return len(roles) == 0 || slices.ContainsFunc(roles, p.HasRole)
+}
@@ -0,0 +1,93 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Backend is the router implementation a route tree is built onto.
+//
+// Paths are passed to Handle in httprouter syntax, i.e. with ":name"
+// parameters and "*name" catch-all parameters, and without constraints,
+// unless the backend is a [ConstraintMatcher].
+// Backends must make the path parameters available to the handler using
+// [httprouter.ParamsKey] in the request context, with catch-all values
+// starting with a slash, so that handlers behave the same on every backend.
+type Backend interface {
+	http.Handler
+	// Handle registers the handler for the method and path.
+	Handle(method, path string, handler http.Handler) error
+}
+
+// ConflictChecker is implemented by backends that restrict which paths can
+// be registered side by side for the same method. [Route.Build] uses it to
+// report conflicts with a description of both routes.
+type ConflictChecker interface {
+	// Conflict reports why the two paths conflict, or returns an empty string
+	// if they don't.
+	Conflict(a, b string) string
+}
+
+// ConstraintMatcher is implemented by backends that match parameter
+// constraints themselves, so that a request failing the constraints of one
+// route can match another one. Their Handle and Conflict methods receive
+// paths with constraints, e.g. "/orders/:id|int".
+type ConstraintMatcher interface {
+	// MatchesConstraints reports whether the backend matches constraints.
+	MatchesConstraints() bool
+}
+
+// matchesConstraints reports whether the backend matches parameter
+// constraints itself.
+func matchesConstraints(backend any) bool {
+	m, ok := backend.(ConstraintMatcher)
+	return ok && m.MatchesConstraints()
+}
+
+// BackendConfig configures a [Backend].
+type BackendConfig struct {
+	// NotFound handles requests that match no route. Nil means the default
+	// of the backend.
+	NotFound http.Handler
+	// MethodNotAllowed handles requests that match the path of a route, but
+	// not its method. Backends set the Allow header before calling it. Nil
+	// means the default of the backend.
+	MethodNotAllowed http.Handler
+}
+
+// NewBackend creates a [Backend] with the given configuration.
+type NewBackend func(cfg BackendConfig) Backend
+
+// httpRouterBackend is a [Backend] based on [httprouter.Router].
+type httpRouterBackend struct {
+	*httprouter.Router
+}
+
+// NewHTTPRouterBackend creates a [Backend] based on [httprouter.Router]. It is
+// the default backend of [Route.Build].
+func NewHTTPRouterBackend(cfg BackendConfig) Backend {
+	router := httprouter.New()
+	router.NotFound = cfg.NotFound
+	router.MethodNotAllowed = cfg.MethodNotAllowed
+	return &httpRouterBackend{Router: router}
+}
+
+// Handle registers the handler for the method and path.
+func (b *httpRouterBackend) Handle(method, path string, handler http.Handler) (err error) {
+	// httprouter panics on invalid paths that slipped through the conflict
+	// detection, e.g. multiple wildcards within one segment.
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+	b.Handler(method, path, handler)
+	return nil
+}
+
+// Conflict reports why the two paths conflict in httprouter.
+func (b *httpRouterBackend) Conflict(x, y string) string {
+	return conflict(x, y)
+}
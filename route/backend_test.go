@@ -0,0 +1,220 @@
+package route_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackendsBehaveTheSame(t *testing.T) {
+	echo := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			params := httprouter.ParamsFromContext(r.Context())
+			w.Write([]byte(name))
+			for _, p := range params {
+				w.Write([]byte(" " + p.Key + "=" + p.Value))
+			}
+		}
+	}
+
+	tree := route.Group("/", []*route.Route{
+		route.Func("GET", "/", echo("root")),
+		route.Group("/users", []*route.Route{
+			route.Func("GET", "", echo("list")),
+			route.Func("POST", "", echo("create")),
+			route.Func("GET", "/:id", echo("show")),
+			route.Func("GET", "/:id/posts/:post", echo("post")),
+			route.Func("GET", "/:id/edit/", echo("edit")),
+		}),
+		route.Func("GET", "/files/*path", echo("file")),
+		route.Func("GET", "/docs/", echo("docs")),
+		route.Func("GET", "/orders/:id|int", echo("order")),
+		route.Func("GET", "/literal/{b}", echo("literal")),
+		route.Func("GET", "/accounts/:account-id", echo("account")),
+		route.Mount("/admin", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("admin " + r.URL.Path))
+		})),
+	})
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		body         string
+		allow        string
+	}{
+		{"GET", "/", 200, "root", ""},
+		{"GET", "/users", 200, "list", ""},
+		{"POST", "/users", 200, "create", ""},
+		{"DELETE", "/users", 405, "", "GET, OPTIONS, POST"},
+		{"OPTIONS", "/users", 200, "", "GET, OPTIONS, POST"},
+		{"HEAD", "/users", 405, "", "GET, OPTIONS, POST"},
+		{"GET", "/users/42", 200, "show id=42", ""},
+		{"GET", "/users/42/posts/7", 200, "post id=42 post=7", ""},
+		{"GET", "/users/42/edit/", 200, "edit id=42", ""},
+		{"GET", "/files/a/b.txt", 200, "file path=/a/b.txt", ""},
+		{"GET", "/files/", 200, "file path=/", ""},
+		{"GET", "/users/", 301, "", ""},
+		{"POST", "/users/", 307, "", ""},
+		{"GET", "/users/42/edit", 301, "", ""},
+		{"GET", "/docs", 301, "", ""},
+		{"GET", "/literal/{b}", 200, "literal", ""},
+		{"GET", "/literal/anything", 404, "", ""},
+		{"GET", "/accounts/7", 200, "account account-id=7", ""},
+		{"GET", "/orders/1", 200, "order id=1", ""},
+		{"GET", "/orders/x", 404, "", ""},
+		{"PUT", "/admin/settings", 200, "admin /settings", ""},
+		{"GET", "/nope", 404, "", ""},
+	} {
+		for name, backend := range map[string]route.NewBackend{
+			"httprouter": route.NewHTTPRouterBackend,
+			"servemux":   route.NewServeMuxBackend,
+			"tree":       route.NewTreeBackend,
+		} {
+			router, err := tree.Build(route.WithBackend(backend), route.WithJSONErrors())
+			assert.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.status, w.Code, "%s: %s %s", name, tc.method, tc.path)
+			assert.Equal(t, tc.allow, w.Header().Get("Allow"), "%s: %s %s", name, tc.method, tc.path)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String(), "%s: %s %s", name, tc.method, tc.path)
+			}
+		}
+	}
+}
+
+func TestServeMuxBackendAllowsStaticSegmentsNextToParameters(t *testing.T) {
+	tree := route.Group("/users", []*route.Route{
+		route.Func("GET", "/:id", noop),
+		route.Func("GET", "/me", noop),
+	})
+
+	_, err := tree.Build()
+	assert.ErrorIs(t, err, route.ErrConflict)
+
+	_, err = tree.Build(route.WithBackend(route.NewServeMuxBackend))
+	assert.NoError(t, err)
+}
+
+func TestTreeBackendMatchesByPrecedence(t *testing.T) {
+	echo := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name))
+			for _, p := range httprouter.ParamsFromContext(r.Context()) {
+				w.Write([]byte(" " + p.Key + "=" + p.Value))
+			}
+		}
+	}
+
+	router, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/users/me", echo("me")),
+		route.Func("GET", "/users/:name", echo("name")),
+		route.Func("GET", "/users/:id|int", echo("id")),
+		route.Func("GET", "/users/:id|int/posts", echo("posts")),
+		route.Func("GET", "/users/:name/files/*path", echo("files")),
+		route.Func("GET", "/docs/*path", echo("docs")),
+		route.Func("GET", "/docs/index", echo("index")),
+		route.Func("POST", "/docs/:page", echo("page")),
+	}).Build(route.WithBackend(route.NewTreeBackend))
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"GET", "/users/me", 200, "me"},
+		{"GET", "/users/mex", 200, "name name=mex"},
+		{"GET", "/users/42", 200, "id id=42"},
+		{"GET", "/users/alice", 200, "name name=alice"},
+		{"GET", "/users/42/posts", 200, "posts id=42"},
+		{"GET", "/users/alice/posts", 404, ""},
+		{"GET", "/users/42/files/a/b", 200, "files name=42 path=/a/b"},
+		{"GET", "/docs/index", 200, "index"},
+		{"GET", "/docs/index/more", 200, "docs path=/index/more"},
+		{"POST", "/docs/index", 200, "page page=index"},
+		{"GET", "/users/42/posts/", 301, ""},
+		{"GET", "/docs", 301, ""},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		assert.Equal(t, tc.status, w.Code, "%s %s", tc.method, tc.path)
+		if tc.body != "" {
+			assert.Equal(t, tc.body, w.Body.String(), "%s %s", tc.method, tc.path)
+		}
+	}
+
+	_, err = route.Group("/users", []*route.Route{
+		route.Func("GET", "/:id", noop),
+		route.Func("GET", "/:name", noop),
+	}).Build(route.WithBackend(route.NewTreeBackend))
+	assert.ErrorIs(t, err, route.ErrConflict)
+}
+
+// discardWriter is a response writer without any allocations.
+type discardWriter struct{ header http.Header }
+
+func (w discardWriter) Header() http.Header         { return w.header }
+func (w discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w discardWriter) WriteHeader(int)             {}
+
+func BenchmarkBackends(b *testing.B) {
+	tree := route.Group("/", []*route.Route{
+		route.Func("GET", "/", noop),
+		route.Group("/users", []*route.Route{
+			route.Func("GET", "", noop),
+			route.Func("POST", "", noop),
+			route.Func("GET", "/:id", noop),
+			route.Func("PUT", "/:id", noop),
+			route.Func("GET", "/:id/posts", noop),
+			route.Func("GET", "/:id/posts/:post", noop),
+		}),
+		route.Func("GET", "/orders/:id|int", noop),
+		route.Func("GET", "/files/*path", noop),
+		route.Func("GET", "/health", noop),
+	})
+
+	for _, backend := range []struct {
+		name string
+		new  route.NewBackend
+	}{
+		{"httprouter", route.NewHTTPRouterBackend},
+		{"tree", route.NewTreeBackend},
+	} {
+		router, err := tree.Build(route.WithBackend(backend.new))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for _, path := range []string{"/health", "/users/42", "/users/42/posts/7", "/orders/42", "/files/a/b/c.txt"} {
+			b.Run(backend.name+path, func(b *testing.B) {
+				r := httptest.NewRequest("GET", path, nil)
+				w := discardWriter{header: make(http.Header)}
+				b.ReportAllocs()
+				for b.Loop() {
+					router.ServeHTTP(w, r)
+				}
+			})
+		}
+	}
+}
+
+func TestServeMuxBackendReportsAmbiguousPatterns(t *testing.T) {
+	for _, paths := range [][2]string{
+		{"/a/:x/b", "/a/b/*r"},
+		{"/a/:x/", "/a/b/*r"},
+	} {
+		_, err := route.Group("/", []*route.Route{
+			route.Group("/", []*route.Route{route.Func("GET", paths[0], noop)}).Name("first"),
+			route.Group("/", []*route.Route{route.Func("GET", paths[1], noop)}).Name("second"),
+		}).Build(route.WithBackend(route.NewServeMuxBackend))
+		assert.ErrorIs(t, err, route.ErrConflict, "%s and %s", paths[0], paths[1])
+		assert.ErrorContains(t, err, "(in / > first)")
+		assert.ErrorContains(t, err, "(in / > second)")
+	}
+}
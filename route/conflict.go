@@ -0,0 +1,86 @@
+package route
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrConflict is returned by [Route.Build] when two routes of the tree cannot
+// be registered side by side.
+var ErrConflict = fmt.Errorf("conflicting routes")
+
+// checkConflicts returns an error describing the first pair of routes that
+// conflict according to the checker. Routes of different hosts never
+// conflict.
+func checkConflicts(routes []Info, checker ConflictChecker) error {
+	pattern := stripConstraints
+	if matchesConstraints(checker) {
+		pattern = func(path string) string { return path }
+	}
+
+	for i, a := range routes {
+		for _, b := range routes[:i] {
+			if a.Method != b.Method || a.Host != b.Host {
+				continue
+			}
+			if reason := checker.Conflict(pattern(b.Path), pattern(a.Path)); reason != "" {
+				return fmt.Errorf("%w: %s: %s and %s", ErrConflict, reason, describe(b), describe(a))
+			}
+		}
+	}
+	return nil
+}
+
+// conflict reports why two path patterns of the same method conflict in
+// httprouter, or an empty string if they don't.
+//
+// httprouter does not allow a wildcard segment next to any other segment at
+// the same position, and a catch-all segment conflicts with everything at its
+// position, including the path ending with a slash right before it.
+func conflict(a, b string) string {
+	if a == b {
+		return "duplicate route"
+	}
+
+	sa, sb := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		x, y := sa[i], sb[i]
+		switch {
+		case x == y:
+			continue
+		case isCatchAll(x) || isCatchAll(y):
+			return "catch-all conflicts with other segment"
+		case isParam(x) && isParam(y):
+			return "wildcard names differ"
+		case isParam(x) || isParam(y):
+			// A trailing slash ends the path before the wildcard segment.
+			if x == "" || y == "" {
+				return ""
+			}
+			return "wildcard conflicts with static segment"
+		default:
+			// Different static segments, the paths diverge.
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// describe returns a human readable description of a route for error
+// messages, including the groups it is nested in.
+func describe(info Info) string {
+	if len(info.Groups) == 0 {
+		return fmt.Sprintf("%s %s%s", info.Method, info.Host, info.Path)
+	}
+
+	groups := make([]string, 0, len(info.Groups))
+	for _, group := range info.Groups {
+		if group.Name != "" {
+			groups = append(groups, group.Name)
+		} else {
+			groups = append(groups, group.Path)
+		}
+	}
+	return fmt.Sprintf("%s %s%s (in %s)", info.Method, info.Host, info.Path, strings.Join(groups, " > "))
+}
@@ -0,0 +1,196 @@
+package route
+
+import (
+	"slices"
+	"strings"
+)
+
+// ChangeKind is the kind of a [Change] between two route listings.
+type ChangeKind string
+
+const (
+	// RouteAdded means a path is served that wasn't before.
+	RouteAdded ChangeKind = "route_added"
+	// RouteRemoved means a method and path are no longer served.
+	RouteRemoved ChangeKind = "route_removed"
+	// MethodsChanged means a path is served with a different set of methods.
+	MethodsChanged ChangeKind = "methods_changed"
+	// ParamsRenamed means the parameters of a path have different names.
+	ParamsRenamed ChangeKind = "params_renamed"
+	// MiddlewareRemoved means a route lost a middleware.
+	MiddlewareRemoved ChangeKind = "middleware_removed"
+)
+
+// Change is a difference between two route listings, see [Diff].
+type Change struct {
+	// Kind is the kind of the change.
+	Kind ChangeKind `json:"kind"`
+	// Method is the method of the affected route, if the change concerns a
+	// single method.
+	Method string `json:"method,omitempty"`
+	// Host is the host pattern of the affected route, if any.
+	Host string `json:"host,omitempty"`
+	// Path is the path of the affected route in the old listing, or in the
+	// new listing for added routes.
+	Path string `json:"path"`
+	// Detail describes the change, e.g. the removed middleware.
+	Detail string `json:"detail,omitempty"`
+	// Breaking reports whether clients or security guarantees may be
+	// affected by the change.
+	Breaking bool `json:"breaking"`
+}
+
+// String returns a stable, single line identification of the change, e.g.
+// "middleware_removed GET /users/:id auth". It is suitable for approval lists.
+func (c Change) String() string {
+	parts := []string{string(c.Kind)}
+	if c.Method != "" {
+		parts = append(parts, c.Method)
+	}
+	parts = append(parts, c.Host+c.Path)
+	if c.Detail != "" {
+		parts = append(parts, c.Detail)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Diff compares two route listings, as returned by [Route.Routes] or loaded
+// from a JSON snapshot of it, and returns the changes from the old to the new
+// listing.
+//
+// Routes are matched by host and path, ignoring the names and constraints of
+// parameters. Removed routes and methods as well as removed middleware are
+// breaking changes. Only middleware named with [Named] or a Name method is
+// compared, as the derived names of other middleware are unstable, see
+// [MiddlewareInfo.Unnamed].
+func Diff(oldRoutes, newRoutes []Info) []Change {
+	oldShapes, oldOrder := shapes(oldRoutes)
+	newShapes, newOrder := shapes(newRoutes)
+
+	var changes []Change
+	for _, shape := range oldOrder {
+		before := oldShapes[shape]
+		after, ok := newShapes[shape]
+		if !ok {
+			for _, info := range before {
+				changes = append(changes, Change{
+					Kind:     RouteRemoved,
+					Method:   info.Method,
+					Host:     info.Host,
+					Path:     info.Path,
+					Breaking: true,
+				})
+			}
+			continue
+		}
+
+		changes = append(changes, diffShape(before, after)...)
+	}
+
+	for _, shape := range newOrder {
+		if _, ok := oldShapes[shape]; !ok {
+			info := newShapes[shape][0]
+			changes = append(changes, Change{
+				Kind:   RouteAdded,
+				Host:   info.Host,
+				Path:   info.Path,
+				Detail: strings.Join(methodsOf(newShapes[shape]), ", "),
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffShape compares the routes of a path in the old and new listing.
+func diffShape(before, after []Info) []Change {
+	var changes []Change
+	first := before[0]
+
+	oldMethods, newMethods := methodsOf(before), methodsOf(after)
+	if !slices.Equal(oldMethods, newMethods) {
+		removed := slices.ContainsFunc(oldMethods, func(m string) bool {
+			return !slices.Contains(newMethods, m)
+		})
+		changes = append(changes, Change{
+			Kind:     MethodsChanged,
+			Host:     first.Host,
+			Path:     first.Path,
+			Detail:   strings.Join(oldMethods, ",") + " -> " + strings.Join(newMethods, ","),
+			Breaking: removed,
+		})
+	}
+
+	for _, b := range before {
+		i := slices.IndexFunc(after, func(a Info) bool { return a.Method == b.Method })
+		if i < 0 {
+			continue
+		}
+		a := after[i]
+
+		if !slices.Equal(b.Params, a.Params) {
+			changes = append(changes, Change{
+				Kind:   ParamsRenamed,
+				Method: b.Method,
+				Host:   b.Host,
+				Path:   b.Path,
+				Detail: a.Path,
+			})
+		}
+
+		for _, mw := range b.Middleware {
+			if mw.Unnamed {
+				continue
+			}
+			if !slices.ContainsFunc(a.Middleware, func(m MiddlewareInfo) bool { return m.Name == mw.Name }) {
+				changes = append(changes, Change{
+					Kind:     MiddlewareRemoved,
+					Method:   b.Method,
+					Host:     b.Host,
+					Path:     b.Path,
+					Detail:   mw.Name,
+					Breaking: true,
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// shapes groups routes by host and path with anonymous parameters. It also
+// returns the shapes in order of appearance.
+func shapes(routes []Info) (map[string][]Info, []string) {
+	var order []string
+	result := make(map[string][]Info)
+	for _, info := range routes {
+		shape := info.Host + anonymousParams(stripConstraints(info.Path))
+		if _, ok := result[shape]; !ok {
+			order = append(order, shape)
+		}
+		result[shape] = append(result[shape], info)
+	}
+	return result, order
+}
+
+// methodsOf returns the sorted, distinct methods of the routes.
+func methodsOf(routes []Info) []string {
+	methods := make([]string, 0, len(routes))
+	for _, info := range routes {
+		methods = append(methods, info.Method)
+	}
+	slices.Sort(methods)
+	return slices.Compact(methods)
+}
+
+// Breaking returns the breaking changes that are not in the approved list.
+// Approved changes are identified by their [Change.String] representation.
+func Breaking(changes []Change, approved []string) []Change {
+	var result []Change
+	for _, c := range changes {
+		if c.Breaking && !slices.Contains(approved, c.String()) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package route_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItDiffsRouteTrees(t *testing.T) {
+	auth := route.Named("auth", route.MiddlewareFunc(func(h http.Handler) http.Handler { return h }))
+	anonymous := route.MiddlewareFunc(func(h http.Handler) http.Handler { return h })
+
+	before, err := route.Group("/", []*route.Route{
+		route.Group("/users", []*route.Route{
+			route.Func("GET", "/:id", noop),
+			route.Func("DELETE", "/:id", noop),
+		}).Middleware(auth),
+		route.Func("GET", "/legacy", noop),
+		route.Func("GET", "/status", noop).Middleware(anonymous),
+	}).Routes()
+	assert.NoError(t, err)
+
+	after, err := route.Group("/", []*route.Route{
+		route.Group("/users", []*route.Route{
+			route.Func("GET", "/:userID", noop),
+		}),
+		route.Func("GET", "/health", noop),
+		route.Func("GET", "/status", noop),
+	}).Routes()
+	assert.NoError(t, err)
+
+	changes := route.Diff(before, after)
+	var ids []string
+	for _, c := range changes {
+		ids = append(ids, c.String())
+	}
+	assert.Equal(t, []string{
+		"methods_changed /users/:id DELETE,GET -> GET",
+		"params_renamed GET /users/:id /users/:userID",
+		"middleware_removed GET /users/:id auth",
+		"route_removed GET /legacy",
+		"route_added /health GET",
+	}, ids)
+
+	breaking := route.Breaking(changes, []string{"route_removed GET /legacy"})
+	assert.Len(t, breaking, 2)
+
+	// Middleware without an explicit name is not compared, its name is unstable
+	assert.True(t, before[3].Middleware[0].Unnamed)
+	assert.False(t, before[0].Middleware[0].Unnamed)
+}
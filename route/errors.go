@@ -0,0 +1,67 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+var (
+	// ErrNotFound is sent by [NotFound] when no route matches the request.
+	ErrNotFound = goweb.NewError("not_found", "not found", http.StatusNotFound)
+	// ErrMethodNotAllowed is sent by [MethodNotAllowed] when a route matches
+	// the request path, but not its method.
+	ErrMethodNotAllowed = goweb.NewError("method_not_allowed", "method not allowed", http.StatusMethodNotAllowed)
+	// ErrBodyTooLarge is sent when the request body exceeds the limit set
+	// with [Route.MaxBody].
+	ErrBodyTooLarge = goweb.NewError("body_too_large", "request body too large", http.StatusRequestEntityTooLarge)
+	// ErrTimeout is sent when handling a request exceeds the limit set with
+	// [Route.Timeout].
+	ErrTimeout = goweb.NewError("timeout", "request timed out", http.StatusServiceUnavailable)
+	// ErrUnavailable is sent by a [Swappable] without a router.
+	ErrUnavailable = goweb.NewError("unavailable", "service unavailable", http.StatusServiceUnavailable)
+	// ErrNotAcceptable is sent when a request fulfils the predicates of none
+	// of the routes of its path. See [Route.When].
+	ErrNotAcceptable = goweb.NewError("not_acceptable", "not acceptable", http.StatusNotAcceptable)
+	// ErrUnsupportedMediaType is sent when the content type of a request is
+	// accepted by none of the routes of its path. See [ContentType].
+	ErrUnsupportedMediaType = goweb.NewError("unsupported_media_type", "unsupported media type", http.StatusUnsupportedMediaType)
+	// ErrUnauthenticated is sent when a route requires authorization, but the
+	// caller is not authenticated. See [Route.Require] and [Route.Roles].
+	ErrUnauthenticated = goweb.NewError("unauthenticated", "authentication required", http.StatusUnauthorized)
+	// ErrForbidden is sent when the caller lacks the scopes or roles required
+	// by a route. See [Route.Require] and [Route.Roles].
+	ErrForbidden = goweb.NewError("forbidden", "forbidden", http.StatusForbidden)
+)
+
+// NotFound responds with [ErrNotFound].
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	_ = goweb.RespondError(w, r, ErrNotFound)
+}
+
+// MethodNotAllowed responds with [ErrMethodNotAllowed].
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	_ = goweb.RespondError(w, r, ErrMethodNotAllowed)
+}
+
+// Recover responds with [goweb.ErrGeneric], whose details are masked. The
+// panic is logged with its stack trace by [goweb.RespondError]. For a
+// [PanicError], the stack of the panicking goroutine is logged.
+//
+// [http.ErrAbortHandler] is panicked again, so the server aborts the response
+// silently.
+func Recover(w http.ResponseWriter, r *http.Request, p any) {
+	if p == http.ErrAbortHandler {
+		panic(p)
+	}
+
+	stack := debug.Stack()
+	if pe, ok := p.(*PanicError); ok {
+		p, stack = pe.Value, pe.Stack
+	}
+
+	err := fmt.Errorf("panic while handling %s %s: %v\n%s", r.Method, r.URL.Path, p, stack)
+	_ = goweb.RespondError(w, r, goweb.ErrGeneric.Wrap(err))
+}
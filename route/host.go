@@ -0,0 +1,122 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// ErrInvalidHost is returned by [Route.Build] when a host pattern is invalid.
+var ErrInvalidHost = fmt.Errorf("invalid host pattern")
+
+// Host creates a route group that only matches requests for the given host.
+//
+// The pattern is either a plain host name such as "admin.example.com" or
+// contains parameters spanning a whole label, such as "{tenant}.example.com".
+// Host parameters can be read like path parameters, using
+// [httprouter.ParamsFromContext]. Path parameters take precedence over host
+// parameters of the same name.
+//
+// Plain host names are matched before patterns, patterns in the order of their
+// declaration. Requests that match no host are dispatched to the routes without
+// a host.
+func Host(pattern string, children []*Route) *Route {
+	return &Route{
+		host:     pattern,
+		children: children,
+	}
+}
+
+// hostParamsKey is the request context key under which host parameters are
+// stored until the route handler merges them with the path parameters.
+type hostParamsKey struct{}
+
+// hostRouter is the router of all routes sharing a host pattern.
+type hostRouter struct {
+	pattern string
+	labels  []string
+	params  bool
+	backend Backend
+}
+
+// newHostRouter creates the router for the given host pattern.
+func newHostRouter(pattern string, backend Backend) (*hostRouter, error) {
+	h := hostRouter{
+		pattern: pattern,
+		labels:  strings.Split(pattern, "."),
+		backend: backend,
+	}
+
+	for i, label := range h.labels {
+		if label == "" {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHost, pattern)
+		}
+		if !strings.ContainsAny(label, "{}") {
+			// Host names are case-insensitive, parameter names are not.
+			h.labels[i] = strings.ToLower(label)
+			continue
+		}
+		if !isHostParam(label) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidHost, pattern)
+		}
+		h.params = true
+	}
+
+	return &h, nil
+}
+
+// match reports whether the host matches the pattern and returns the values
+// of the host parameters.
+func (h *hostRouter) match(host string) (httprouter.Params, bool) {
+	labels := strings.Split(host, ".")
+	if len(labels) != len(h.labels) {
+		return nil, false
+	}
+
+	var params httprouter.Params
+	for i, label := range h.labels {
+		switch {
+		case isHostParam(label):
+			if labels[i] == "" {
+				return nil, false
+			}
+			params = append(params, httprouter.Param{Key: label[1 : len(label)-1], Value: labels[i]})
+		case label != labels[i]:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// isHostParam reports whether a host label is a parameter.
+func isHostParam(label string) bool {
+	return len(label) > 2 && label[0] == '{' && label[len(label)-1] == '}' && !strings.ContainsAny(label[1:len(label)-1], "{}")
+}
+
+// requestHost returns the lowercase host of the request without port.
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// withHostParams merges the host parameters of the request into its path
+// parameters.
+func withHostParams(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hp, ok := r.Context().Value(hostParamsKey{}).(httprouter.Params); ok {
+			pp := httprouter.ParamsFromContext(r.Context())
+			params := make(httprouter.Params, 0, len(pp)+len(hp))
+			params = append(append(params, pp...), hp...)
+			r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, params))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
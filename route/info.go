@@ -0,0 +1,295 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// Info describes a single route of a route tree with its effective settings,
+// i.e. with everything inherited from its ancestors applied.
+type Info struct {
+	// Method is the HTTP method of the route.
+	Method string `json:"method"`
+	// Path is the full path pattern of the route, including all prefixes and
+	// parameter constraints.
+	Path string `json:"path"`
+	// Host is the host pattern the route is restricted to, if any.
+	Host string `json:"host,omitempty"`
+	// Name is the name of the route, if any.
+	Name string `json:"name,omitempty"`
+	// Params are the names of the path parameters in order of appearance.
+	Params []string `json:"params,omitempty"`
+	// Middleware is the middleware chain of the route, outermost first.
+	Middleware []MiddlewareInfo `json:"middleware,omitempty"`
+	// Meta is the metadata attached to the route and its ancestors.
+	Meta map[string]any `json:"meta,omitempty"`
+	// Tags are the tags of the route and its ancestors.
+	Tags []string `json:"tags,omitempty"`
+	// Groups is the chain of ancestors of the route, outermost first.
+	Groups []GroupInfo `json:"groups,omitempty"`
+	// Version is the API version of the route, if any. See [Version].
+	Version string `json:"version,omitempty"`
+	// Deprecated reports whether the route is deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Sunset is the time the route will be removed, if known.
+	Sunset time.Time `json:"sunset,omitzero"`
+	// Timeout is the time limit for handling a request, if any.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// MaxBody is the maximum size of the request body in bytes, if any.
+	MaxBody int64 `json:"max_body,omitempty"`
+	// Scopes are the scopes a caller needs to be granted, all of them.
+	Scopes []string `json:"scopes,omitempty"`
+	// Roles are the roles of which a caller needs to have at least one.
+	Roles []string `json:"roles,omitempty"`
+	// Description is the human readable description of the route, if any.
+	Description string `json:"description,omitempty"`
+	// Predicates describe the conditions on requests of the route and its
+	// ancestors. See [Route.When].
+	Predicates []string `json:"predicates,omitempty"`
+
+	handler http.Handler
+	// versionPath is the full path of the [Version] group of the route.
+	versionPath string
+	// versions are the versioned routes a negotiated route dispatches to.
+	versions []Info
+	// predicates are the conditions described by Predicates.
+	predicates []Predicate
+	// candidates are the routes with predicates a route dispatches to.
+	candidates []Info
+}
+
+// MiddlewareInfo describes a middleware in the chain of a route.
+type MiddlewareInfo struct {
+	// Name is the name of the middleware. See [Named].
+	Name string `json:"name"`
+	// Unnamed reports whether the name is derived from the function or type
+	// of the middleware. Such names, e.g. "pkg.Setup.func3" for closures, can
+	// change with unrelated code edits.
+	Unnamed bool `json:"unnamed,omitempty"`
+	// Middleware is the middleware itself.
+	Middleware Middleware `json:"-"`
+}
+
+// GroupInfo describes an ancestor of a route.
+type GroupInfo struct {
+	// Path is the full path of the group, including all prefixes.
+	Path string `json:"path"`
+	// Name is the name of the group, if any.
+	Name string `json:"name,omitempty"`
+}
+
+// HasTag reports whether the route has the given tag.
+func (i Info) HasTag(tag string) bool {
+	return slices.Contains(i.Tags, tag)
+}
+
+// infoKey is the request context key under which the [Info] of the matched
+// route is stored.
+type infoKey struct{}
+
+// FromContext returns the [Info] of the route that matched the request.
+func FromContext(ctx context.Context) (Info, bool) {
+	info, ok := ctx.Value(infoKey{}).(*Info)
+	if !ok {
+		return Info{}, false
+	}
+	return *info, true
+}
+
+// withInfo stores the route info in the request context.
+func withInfo(info *Info, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), infoKey{}, info)))
+	})
+}
+
+// Routes returns descriptions of the route and all its descendants that have
+// a handler, in tree order. A route serving several methods is described once
+// per method.
+func (r *Route) Routes() ([]Info, error) {
+	var routes []Info
+	err := r.Walk(func(info Info) error {
+		routes = append(routes, info)
+		return nil
+	})
+	return routes, err
+}
+
+// Walk calls fn for the route and each of its descendants that has a handler,
+// in tree order, once per method. If fn returns an error, walking stops and the error is
+// returned.
+func (r *Route) Walk(fn func(Info) error) error {
+	return r.walk(Info{Path: "/"}, fn)
+}
+
+// walk calls fn for the route and its descendants. The parent info carries
+// the settings inherited from the ancestors.
+func (r *Route) walk(parent Info, fn func(Info) error) error {
+	// Prepend the parent path prefix
+	path := joinPath(parent.Path, r.path)
+
+	if r.err != nil {
+		return fmt.Errorf("route %s: %w", path, r.err)
+	}
+
+	info := Info{
+		Path:        path,
+		Host:        parent.Host,
+		Name:        r.name,
+		Description: r.description,
+		Params:      paramNames(path),
+		Middleware:  slices.Clone(parent.Middleware),
+		Meta:        maps.Clone(parent.Meta),
+		Tags:        parent.Tags,
+		Version:     parent.Version,
+		Deprecated:  parent.Deprecated || r.deprecated,
+		Sunset:      parent.Sunset,
+		Timeout:     parent.Timeout,
+		MaxBody:     parent.MaxBody,
+		Scopes:      parent.Scopes,
+		Roles:       parent.Roles,
+		Predicates:  parent.Predicates,
+		predicates:  parent.predicates,
+		versionPath: parent.versionPath,
+		Groups:      parent.Groups,
+		handler:     r.handler,
+	}
+
+	if r.host != "" {
+		info.Host = r.host
+	}
+	if r.version != "" {
+		info.Version = r.version
+		info.versionPath = path
+	}
+	if !r.sunset.IsZero() {
+		info.Sunset = r.sunset
+	}
+	if r.timeout != 0 {
+		info.Timeout = max(r.timeout, 0)
+	}
+	if r.maxBody != 0 {
+		info.MaxBody = max(r.maxBody, 0)
+	}
+
+	// Drop the inherited middleware the route opts out of
+	if len(r.without) > 0 {
+		info.Middleware = slices.DeleteFunc(info.Middleware, func(mw MiddlewareInfo) bool {
+			return slices.Contains(r.without, mw.Name)
+		})
+	}
+
+	// Append the route's own middleware to the inherited chain
+	for _, mw := range r.middleware {
+		name, named := middlewareName(mw)
+		info.Middleware = append(info.Middleware, MiddlewareInfo{
+			Name:       name,
+			Unnamed:    !named,
+			Middleware: mw,
+		})
+	}
+
+	if len(r.meta) > 0 {
+		if info.Meta == nil {
+			info.Meta = make(map[string]any, len(r.meta))
+		}
+		maps.Copy(info.Meta, r.meta)
+	}
+
+	info.Tags = appendUnique(info.Tags, r.tags...)
+	info.Scopes = appendUnique(info.Scopes, r.scopes...)
+	for _, p := range r.predicates {
+		info.Predicates = append(slices.Clip(info.Predicates), p.String())
+		info.predicates = append(slices.Clip(info.predicates), p)
+	}
+	if len(r.roles) > 0 {
+		info.Roles = appendUnique(nil, r.roles...)
+	}
+
+	if r.handler != nil {
+		for _, method := range r.methods {
+			if method == "" {
+				return fmt.Errorf("route %s: %w", path, ErrEmptyMethod)
+			}
+			info.Method = method
+			if err := fn(info); err != nil {
+				return err
+			}
+		}
+		info.Method = ""
+	}
+
+	// Recursively walk the route's children.
+	if len(r.children) > 0 {
+		info.Groups = append(slices.Clone(parent.Groups), GroupInfo{
+			Path: path,
+			Name: r.name,
+		})
+		for _, child := range r.children {
+			if err := child.walk(info, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Named gives a middleware a name. The name shows up in [Info] and in
+// [Route.Dump].
+func Named(name string, mw Middleware) Middleware {
+	return namedMiddleware{Middleware: mw, name: name}
+}
+
+// namedMiddleware is a middleware with a name.
+type namedMiddleware struct {
+	Middleware
+	name string
+}
+
+// Name returns the name of the middleware.
+func (m namedMiddleware) Name() string {
+	return m.name
+}
+
+// ForRoute passes the route info on to the named middleware, if it is a
+// [RouteMiddleware].
+func (m namedMiddleware) ForRoute(info Info, next http.Handler) http.Handler {
+	return wrap(m.Middleware, info, next)
+}
+
+// middlewareName returns the name of the middleware and whether it was named
+// explicitly. Middleware created with [Named] or implementing a Name method
+// reports its own name; for functions, the function name is used. Otherwise,
+// the type name is returned.
+func middlewareName(mw Middleware) (string, bool) {
+	if n, ok := mw.(interface{ Name() string }); ok {
+		return n.Name(), true
+	}
+
+	switch mw.(type) {
+	case MiddlewareFunc, RouteMiddlewareFunc:
+		if fn := runtime.FuncForPC(reflect.ValueOf(mw).Pointer()); fn != nil {
+			return fn.Name(), false
+		}
+	}
+
+	return fmt.Sprintf("%T", mw), false
+}
+
+// appendUnique appends the values missing from the list. The list is never
+// modified in place, as it is shared with the siblings of a route.
+func appendUnique(list []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(list, v) {
+			list = append(slices.Clip(list), v)
+		}
+	}
+	return list
+}
@@ -0,0 +1,152 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sehrgutesoftware/goweb"
+	"github.com/sehrgutesoftware/goweb/validate"
+)
+
+var (
+	// ErrInvalidBody is returned by [JSON] handlers when the request body
+	// cannot be decoded.
+	ErrInvalidBody = goweb.NewError("invalid_body", "invalid request body", http.StatusBadRequest)
+	// ErrInvalidQueryParam is returned by [JSON] handlers when a query
+	// parameter cannot be parsed.
+	ErrInvalidQueryParam = goweb.NewError("invalid_query_param", "invalid query parameter", http.StatusBadRequest)
+)
+
+// JSON creates a route from a typed handler function.
+//
+// The request body is decoded as JSON into a value of type Req. Struct fields
+// tagged with `path:"name"` or `query:"name"` are filled from the path and
+// query parameters of the same name. If Req is a struct, it is validated
+// using its `validate` tags, see [validate.Struct].
+//
+// The result of fn is sent using [goweb.Respond], or [goweb.RespondError] if
+// fn returns an error. Errors while decoding or validating the request are
+// sent the same way, without calling fn.
+func JSON[Req, Resp any](method, path string, fn func(ctx context.Context, req Req) (Resp, error)) *Route {
+	h, err := newJSONHandler(fn)
+	return &Route{
+		methods: []string{method},
+		path:    path,
+		handler: h,
+		err:     err,
+	}
+}
+
+// jsonHandler is the handler of a [JSON] route.
+type jsonHandler[Req, Resp any] struct {
+	fn        func(ctx context.Context, req Req) (Resp, error)
+	fields    []boundField
+	validator validate.Validator
+}
+
+// boundField is a struct field that is filled from a path or query parameter.
+type boundField struct {
+	index  []int
+	source string
+	name   string
+}
+
+// newJSONHandler creates the handler for the given function, preparing the
+// field bindings and validator of the request type.
+func newJSONHandler[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error)) (*jsonHandler[Req, Resp], error) {
+	h := jsonHandler[Req, Resp]{fn: fn}
+
+	typ := reflect.TypeFor[Req]()
+	if typ.Kind() != reflect.Struct {
+		return &h, nil
+	}
+
+	for _, field := range reflect.VisibleFields(typ) {
+		for _, source := range []string{"path", "query"} {
+			if name, ok := field.Tag.Lookup(source); ok && name != "" {
+				h.fields = append(h.fields, boundField{
+					index:  field.Index,
+					source: source,
+					name:   name,
+				})
+			}
+		}
+	}
+
+	var zero Req
+	validator, err := validate.Struct(zero)
+	if err != nil {
+		return nil, err
+	}
+	h.validator = validator
+
+	return &h, nil
+}
+
+// ServeHTTP decodes and validates the request, calls the handler function and
+// sends its result.
+func (h *jsonHandler[Req, Resp]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := h.decode(r)
+	if err == nil && h.validator != nil {
+		err = h.validator.Validate(req)
+	}
+	if err != nil {
+		_ = goweb.RespondError(w, r, err)
+		return
+	}
+
+	resp, err := h.fn(r.Context(), req)
+	if err != nil {
+		_ = goweb.RespondError(w, r, err)
+		return
+	}
+
+	_ = goweb.Respond(w, r, resp)
+}
+
+// decode creates the request value from the body, path and query parameters.
+func (h *jsonHandler[Req, Resp]) decode(r *http.Request) (Req, error) {
+	var req Req
+
+	if r.Body != nil && r.Body != http.NoBody {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return req, ErrBodyTooLarge.Wrap(err).Apply(map[string]any{"limit": maxBytesErr.Limit})
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return req, ErrInvalidBody.Wrap(err)
+		}
+	}
+
+	if len(h.fields) == 0 {
+		return req, nil
+	}
+
+	v := reflect.ValueOf(&req).Elem()
+	params := httprouter.ParamsFromContext(r.Context())
+	query := r.URL.Query()
+	for _, f := range h.fields {
+		dst := v.FieldByIndex(f.index).Addr().Interface()
+		switch f.source {
+		case "path":
+			if err := parseValue(params.ByName(f.name), dst); err != nil {
+				return req, ErrInvalidPathParam.Apply(map[string]any{"param": f.name})
+			}
+		case "query":
+			if !query.Has(f.name) {
+				continue
+			}
+			if err := parseValue(query.Get(f.name), dst); err != nil {
+				return req, ErrInvalidQueryParam.Apply(map[string]any{"param": f.name})
+			}
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,58 @@
+package route_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type renameUser struct {
+	ID     int64  `path:"id"`
+	Notify bool   `query:"notify"`
+	Name   string `json:"name" validate:"between:1:8"`
+}
+
+type renamedUser struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Notify bool   `json:"notify"`
+}
+
+func TestItDecodesValidatesAndRespondsWithJSON(t *testing.T) {
+	router, err := route.JSON("PUT", "/users/:id", func(ctx context.Context, req renameUser) (renamedUser, error) {
+		return renamedUser{ID: req.ID, Name: req.Name, Notify: req.Notify}, nil
+	}).Build()
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/users/42?notify=true", strings.NewReader(`{"name":"alice"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":42,"name":"alice","notify":true}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/users/42", strings.NewReader(`{"name":"a very long name"}`)))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_entity"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/users/abc", strings.NewReader(`{"name":"alice"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_path_param"`)
+	assert.Contains(t, w.Body.String(), `"message":"invalid path parameter"`)
+	assert.NotContains(t, w.Body.String(), "strconv")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/users/42?notify=maybe", strings.NewReader(`{"name":"alice"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"message":"invalid query parameter"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/users/42", strings.NewReader(`{`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"invalid_body"`)
+}
@@ -0,0 +1,159 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// Timeout limits the time for handling requests of the route and its
+// children. The request context carries the deadline. If the limit is
+// exceeded, [ErrTimeout] is sent and the handler's response is discarded.
+//
+// Responses are buffered until the handler returns, so streaming responses
+// are not possible with a timeout. A negative duration removes an inherited
+// timeout.
+func (r *Route) Timeout(d time.Duration) *Route {
+	r.timeout = d
+	return r
+}
+
+// MaxBody limits the size of request bodies of the route and its children to
+// n bytes. Requests announcing a larger body are rejected with
+// [ErrBodyTooLarge], reading beyond the limit fails with an
+// [http.MaxBytesError]. A negative size removes an inherited limit.
+func (r *Route) MaxBody(n int64) *Route {
+	r.maxBody = n
+	return r
+}
+
+// limitBody rejects and limits request bodies larger than n bytes.
+func limitBody(n int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > n {
+			_ = goweb.RespondError(w, r, ErrBodyTooLarge.Apply(map[string]any{"limit": n}))
+			return
+		}
+
+		r2 := *r
+		r2.Body = http.MaxBytesReader(w, r.Body, n)
+		next.ServeHTTP(w, &r2)
+	})
+}
+
+// withTimeout sends [ErrTimeout] if the handler does not finish within d.
+//
+// It works like [http.TimeoutHandler], but sends the error using
+// [goweb.RespondError].
+func withTimeout(d time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		tw := timeoutWriter{header: make(http.Header)}
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					if p != http.ErrAbortHandler {
+						p = &PanicError{Value: p, Stack: debug.Stack()}
+					}
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(&tw, r)
+			close(done)
+		}()
+
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			maps.Copy(w.Header(), tw.header)
+			if tw.code == 0 {
+				tw.code = http.StatusOK
+			}
+			w.WriteHeader(tw.code)
+			_, _ = w.Write(tw.buf.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			tw.timedOut = true
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				_ = goweb.RespondError(w, r, ErrTimeout)
+			}
+		}
+	})
+}
+
+// PanicError is panicked by routes with a [Route.Timeout] if their handler
+// panics. The handler runs in its own goroutine, so the panic is passed on
+// to the request goroutine with the stack of the handler's goroutine.
+type PanicError struct {
+	// Value is the value passed to panic by the handler.
+	Value any
+	// Stack is the stack trace of the handler's goroutine.
+	Stack []byte
+}
+
+// Error returns the panic value as a string.
+func (e *PanicError) Error() string {
+	return fmt.Sprint(e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// timeoutWriter buffers the response of a handler running with a timeout.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+// Header returns the buffered response headers.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// Write buffers the response body.
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = http.StatusOK
+	}
+	return tw.buf.Write(p)
+}
+
+// WriteHeader records the status code of the response.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
@@ -0,0 +1,189 @@
+package route
+
+import "strings"
+
+// WarningKind is the kind of a [Warning] about a route tree.
+type WarningKind string
+
+const (
+	// EmptyGroup means a route has neither a handler nor children.
+	EmptyGroup WarningKind = "empty_group"
+	// MissingHandler means a route has methods, but no handler.
+	MissingHandler WarningKind = "missing_handler"
+	// MissingMethod means a route has a handler, but no methods, so it is
+	// never registered. An empty method is an error, see [ErrEmptyMethod].
+	MissingMethod WarningKind = "missing_method"
+	// TrailingSlash means a path ends with a slash, unlike the other paths of
+	// the tree, or the other way round.
+	TrailingSlash WarningKind = "trailing_slash"
+	// ParamNames means a parameter has a different name than the parameter
+	// at the same position of another path.
+	ParamNames WarningKind = "param_names"
+	// DuplicateMiddleware means a middleware is applied more than once to a
+	// route.
+	DuplicateMiddleware WarningKind = "duplicate_middleware"
+)
+
+// Warning is a suspicious part of a route tree, see [Route.Lint].
+type Warning struct {
+	// Kind is the kind of the warning.
+	Kind WarningKind `json:"kind"`
+	// Method is the method of the affected route, if the warning concerns a
+	// single method.
+	Method string `json:"method,omitempty"`
+	// Host is the host pattern of the affected route, if any.
+	Host string `json:"host,omitempty"`
+	// Path is the full path of the affected route.
+	Path string `json:"path"`
+	// Detail describes the warning, e.g. the duplicated middleware.
+	Detail string `json:"detail,omitempty"`
+}
+
+// String returns a single line description of the warning, e.g.
+// "duplicate_middleware GET /users/:id auth".
+func (w Warning) String() string {
+	parts := []string{string(w.Kind)}
+	if w.Method != "" {
+		parts = append(parts, w.Method)
+	}
+	parts = append(parts, w.Host+w.Path)
+	if w.Detail != "" {
+		parts = append(parts, w.Detail)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Lint reports suspicious parts of the route tree which [Route.Build]
+// accepts, like groups without children or parameters that are named
+// differently in different paths. The error is the one [Route.Build] would
+// return for an invalid tree.
+func (r *Route) Lint() ([]Warning, error) {
+	routes, err := r.Routes()
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := r.lintTree("/", "")
+	warnings = append(warnings, lintTrailingSlashes(routes)...)
+	warnings = append(warnings, lintParamNames(routes)...)
+	warnings = append(warnings, lintMiddleware(routes)...)
+
+	return warnings, nil
+}
+
+// lintTree reports routes of the tree that are never registered.
+func (r *Route) lintTree(parent, host string) []Warning {
+	path := joinPath(parent, r.path)
+	if r.host != "" {
+		host = r.host
+	}
+
+	var warnings []Warning
+	switch {
+	case r.handler == nil && len(r.children) == 0 && len(r.methods) == 0:
+		warnings = append(warnings, Warning{Kind: EmptyGroup, Host: host, Path: path})
+	case r.handler == nil && len(r.methods) > 0:
+		warnings = append(warnings, Warning{Kind: MissingHandler, Host: host, Path: path, Detail: strings.Join(r.methods, ",")})
+	case r.handler != nil && len(r.methods) == 0:
+		warnings = append(warnings, Warning{Kind: MissingMethod, Host: host, Path: path})
+	}
+
+	for _, child := range r.children {
+		warnings = append(warnings, child.lintTree(path, host)...)
+	}
+
+	return warnings
+}
+
+// lintTrailingSlashes reports the paths whose trailing slash differs from
+// the majority of the paths.
+func lintTrailingSlashes(routes []Info) []Warning {
+	var with, without []Info
+	for _, info := range routes {
+		switch {
+		case info.Path == "/":
+		case strings.HasSuffix(info.Path, "/"):
+			with = append(with, info)
+		default:
+			without = append(without, info)
+		}
+	}
+
+	if len(with) == 0 || len(without) == 0 {
+		return nil
+	}
+
+	minority, detail := with, "has a trailing slash"
+	if len(without) < len(with) {
+		minority, detail = without, "has no trailing slash"
+	}
+
+	warnings := make([]Warning, 0, len(minority))
+	for _, info := range minority {
+		warnings = append(warnings, Warning{
+			Kind:   TrailingSlash,
+			Method: info.Method,
+			Host:   info.Host,
+			Path:   info.Path,
+			Detail: detail,
+		})
+	}
+	return warnings
+}
+
+// lintParamNames reports parameters that are named differently than the
+// first parameter at the same position of a path with the same prefix.
+func lintParamNames(routes []Info) []Warning {
+	type position struct{ host, prefix string }
+
+	names := make(map[position]string)
+	reported := make(map[string]bool)
+	var warnings []Warning
+	for _, info := range routes {
+		segments := strings.Split(stripConstraints(info.Path), "/")
+		for i, segment := range segments {
+			if !isParam(segment) && !isCatchAll(segment) {
+				continue
+			}
+
+			pos := position{info.Host, anonymousParams(strings.Join(segments[:i], "/"))}
+			name, ok := names[pos]
+			if !ok {
+				names[pos] = segment
+				continue
+			}
+			if name != segment && !reported[info.Host+info.Path] {
+				reported[info.Host+info.Path] = true
+				warnings = append(warnings, Warning{
+					Kind:   ParamNames,
+					Host:   info.Host,
+					Path:   info.Path,
+					Detail: segment + " != " + name,
+				})
+			}
+		}
+	}
+	return warnings
+}
+
+// lintMiddleware reports middleware that is applied more than once to a
+// route.
+func lintMiddleware(routes []Info) []Warning {
+	var warnings []Warning
+	for _, info := range routes {
+		seen := make(map[string]bool, len(info.Middleware))
+		for _, mw := range info.Middleware {
+			if seen[mw.Name] {
+				warnings = append(warnings, Warning{
+					Kind:   DuplicateMiddleware,
+					Method: info.Method,
+					Host:   info.Host,
+					Path:   info.Path,
+					Detail: mw.Name,
+				})
+			}
+			seen[mw.Name] = true
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,85 @@
+package route
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// autoMethods returns the routes extended by synthesized HEAD routes for every
+// GET route and OPTIONS routes for every path.
+//
+// OPTIONS routes run the middleware and belong to the groups the routes of
+// their path have in common, so e.g. CORS middleware of a group applies.
+func autoMethods(routes []Info) []Info {
+	type location struct{ host, path string }
+
+	var locations []location
+	methods := make(map[location][]string)
+	inherited := make(map[location]Info)
+	for _, info := range routes {
+		loc := location{info.Host, info.Path}
+		if _, ok := methods[loc]; !ok {
+			locations = append(locations, loc)
+			inherited[loc] = info
+		} else {
+			common := inherited[loc]
+			common.Middleware = commonPrefix(common.Middleware, info.Middleware, func(a, b MiddlewareInfo) bool {
+				return a.Name == b.Name
+			})
+			common.Groups = commonPrefix(common.Groups, info.Groups, func(a, b GroupInfo) bool {
+				return a == b
+			})
+			inherited[loc] = common
+		}
+		methods[loc] = append(methods[loc], info.Method)
+	}
+
+	for _, info := range routes {
+		loc := location{info.Host, info.Path}
+		if info.Method == http.MethodGet && !slices.Contains(methods[loc], http.MethodHead) {
+			head := info
+			head.Method = http.MethodHead
+			head.Name = ""
+			routes = append(routes, head)
+			methods[loc] = append(methods[loc], http.MethodHead)
+		}
+	}
+
+	for _, loc := range locations {
+		if slices.Contains(methods[loc], http.MethodOptions) {
+			continue
+		}
+
+		allow := append(slices.Clone(methods[loc]), http.MethodOptions)
+		slices.Sort(allow)
+		routes = append(routes, Info{
+			Method:     http.MethodOptions,
+			Path:       loc.path,
+			Host:       loc.host,
+			Params:     paramNames(loc.path),
+			Middleware: inherited[loc].Middleware,
+			Groups:     inherited[loc].Groups,
+			handler:    allowHandler(strings.Join(slices.Compact(allow), ", ")),
+		})
+	}
+
+	return routes
+}
+
+// commonPrefix returns the longest common prefix of the two lists.
+func commonPrefix[T any](a, b []T, eq func(T, T) bool) []T {
+	n := 0
+	for n < min(len(a), len(b)) && eq(a[n], b[n]) {
+		n++
+	}
+	return a[:n:n]
+}
+
+// allowHandler answers a request with the given Allow header and no content.
+func allowHandler(allow string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,60 @@
+package route
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// mountParam is the name of the catch-all parameter of mounted handlers.
+const mountParam = "mountpath"
+
+// allMethods are the methods a mounted handler is registered for.
+var allMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
+// Mount creates a route that delegates all requests below the prefix to the
+// given handler, regardless of their method.
+//
+// The prefix, including the prefixes of the route's ancestors, is stripped
+// from the request path before it is passed to the handler. Middleware of the
+// route and its ancestors is applied as usual.
+func Mount(prefix string, handler http.Handler) *Route {
+	return &Route{
+		methods: allMethods,
+		path:    strings.TrimSuffix(prefix, "/") + "/*" + mountParam,
+		handler: stripMount(handler),
+	}
+}
+
+// stripMount replaces the request path with the value of the mount's
+// catch-all parameter.
+func stripMount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := httprouter.ParamsFromContext(r.Context()).ByName(mountParam)
+		prefix := strings.TrimSuffix(r.URL.Path, path)
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = path
+		r2.URL.RawPath = ""
+		if rp, ok := strings.CutPrefix(r.URL.RawPath, prefix); ok {
+			r2.URL.RawPath = rp
+		}
+
+		next.ServeHTTP(w, r2)
+	})
+}
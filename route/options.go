@@ -0,0 +1,96 @@
+package route
+
+import "net/http"
+
+// Option configures how [Route.Build] builds a route tree.
+type Option func(*options)
+
+// options holds the settings of a single [Route.Build] call.
+type options struct {
+	// autoMethods enables synthesized HEAD and OPTIONS routes.
+	autoMethods bool
+	// notFound handles requests that match no route.
+	notFound http.Handler
+	// methodNotAllowed handles requests that match a route, but not its
+	// method.
+	methodNotAllowed http.Handler
+	// panicHandler handles panics of route handlers.
+	panicHandler func(http.ResponseWriter, *http.Request, any)
+	// backend creates the backends the routes are registered with.
+	backend NewBackend
+	// versioning enables version negotiation for [Version] groups.
+	versioning *VersionOptions
+	// authorizer resolves the callers of routes with requirements.
+	authorizer Authorizer
+}
+
+// WithAutoMethods makes Build register a HEAD route for every GET route and an
+// OPTIONS route for every path, unless the tree defines them explicitly.
+//
+// OPTIONS requests are answered with an Allow header listing the methods of
+// the path. The same list is sent with 405 Method Not Allowed responses.
+func WithAutoMethods() Option {
+	return func(o *options) {
+		o.autoMethods = true
+	}
+}
+
+// WithNotFound sets the handler for requests that match no route.
+func WithNotFound(h http.Handler) Option {
+	return func(o *options) {
+		o.notFound = h
+	}
+}
+
+// WithMethodNotAllowed sets the handler for requests that match the path of a
+// route, but not its method. The Allow header is set before the handler is
+// called.
+func WithMethodNotAllowed(h http.Handler) Option {
+	return func(o *options) {
+		o.methodNotAllowed = h
+	}
+}
+
+// WithPanicHandler sets the function that handles panics of route handlers.
+// It receives the value passed to panic.
+func WithPanicHandler(fn func(http.ResponseWriter, *http.Request, any)) Option {
+	return func(o *options) {
+		o.panicHandler = fn
+	}
+}
+
+// WithJSONErrors installs JSON handlers for unmatched routes, unmatched
+// methods and panics, see [NotFound], [MethodNotAllowed] and [Recover].
+func WithJSONErrors() Option {
+	return func(o *options) {
+		o.notFound = http.HandlerFunc(NotFound)
+		o.methodNotAllowed = http.HandlerFunc(MethodNotAllowed)
+		o.panicHandler = Recover
+	}
+}
+
+// WithBackend sets the router implementation the routes are registered with.
+// The default is [NewHTTPRouterBackend].
+func WithBackend(backend NewBackend) Option {
+	return func(o *options) {
+		o.backend = backend
+	}
+}
+
+// WithVersionNegotiation makes the routes of [Version] groups available
+// without their version prefix as well. The version is then selected by the
+// client, see [VersionOptions].
+func WithVersionNegotiation(opts VersionOptions) Option {
+	return func(o *options) {
+		o.versioning = &opts
+	}
+}
+
+// WithAuthorizer sets the authorizer that checks the requirements of routes,
+// see [Route.Require] and [Route.Roles]. Building a tree with requirements
+// fails without an authorizer.
+func WithAuthorizer(a Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = a
+	}
+}
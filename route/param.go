@@ -0,0 +1,151 @@
+package route
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sehrgutesoftware/goweb"
+)
+
+var (
+	// ErrInvalidPathParam is returned by [Param] when a path parameter cannot
+	// be parsed into the requested type.
+	ErrInvalidPathParam = goweb.NewError("invalid_path_param", "invalid path parameter", http.StatusBadRequest)
+	// ErrUnknownConstraint is returned by [Route.Build] when a path contains
+	// an unknown parameter constraint.
+	ErrUnknownConstraint = fmt.Errorf("unknown parameter constraint")
+)
+
+// Param returns the path parameter with the given name, parsed into T.
+//
+// T can be a string, bool, integer or float type, or implement
+// [encoding.TextUnmarshaler]. If the parameter cannot be parsed,
+// [ErrInvalidPathParam] is returned with the parameter name as detail.
+func Param[T any](r *http.Request, name string) (T, error) {
+	var value T
+	raw := httprouter.ParamsFromContext(r.Context()).ByName(name)
+	if err := parseValue(raw, &value); err != nil {
+		return value, ErrInvalidPathParam.Apply(map[string]any{"param": name})
+	}
+	return value, nil
+}
+
+// parseValue parses a raw string into the value dst points to.
+func parseValue(raw string, dst any) error {
+	if u, ok := dst.(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// uuidPattern matches the textual representation of a UUID.
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// constraints are the checks available as inline parameter constraints, as in
+// "/users/:id|int".
+var constraints = map[string]func(string) bool{
+	"int": func(s string) bool {
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	},
+	"uint": func(s string) bool {
+		_, err := strconv.ParseUint(s, 10, 64)
+		return err == nil
+	},
+	"float": func(s string) bool {
+		_, err := strconv.ParseFloat(s, 64)
+		return err == nil
+	},
+	"uuid": uuidPattern.MatchString,
+	"alpha": func(s string) bool {
+		return s != "" && strings.IndexFunc(s, func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z')
+		}) < 0
+	},
+	"alnum": func(s string) bool {
+		return s != "" && strings.IndexFunc(s, func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+		}) < 0
+	},
+}
+
+// paramConstraint is a constraint on a single path parameter.
+type paramConstraint struct {
+	param string
+	check func(string) bool
+}
+
+// parseConstraints returns the parameter constraints of a path pattern.
+func parseConstraints(path string) ([]paramConstraint, error) {
+	var result []paramConstraint
+	for segment := range strings.SplitSeq(path, "/") {
+		if !isParam(segment) && !isCatchAll(segment) {
+			continue
+		}
+
+		name, constraint := splitParam(segment)
+		if constraint == "" {
+			continue
+		}
+
+		check, ok := constraints[constraint]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownConstraint, constraint)
+		}
+		result = append(result, paramConstraint{param: name, check: check})
+	}
+	return result, nil
+}
+
+// checkConstraints responds with the notFound handler if a path parameter
+// violates its constraint.
+func checkConstraints(cs []paramConstraint, notFound http.Handler, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := httprouter.ParamsFromContext(r.Context())
+		for _, c := range cs {
+			if !c.check(strings.TrimPrefix(params.ByName(c.param), "/")) {
+				notFound.ServeHTTP(w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,117 @@
+package route
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// joinPath appends a path element to a base path. Unlike [url.JoinPath], it
+// does not escape the path, so that parameter constraints survive.
+func joinPath(base, elem string) string {
+	if elem == "" {
+		return base
+	}
+
+	joined := path.Join(base, elem)
+	if strings.HasSuffix(elem, "/") && !strings.HasSuffix(joined, "/") {
+		joined += "/"
+	}
+	return joined
+}
+
+// expand fills the parameters in an httprouter path pattern with the given
+// values.
+func expand(path string, params map[string]string) (string, error) {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+
+		switch segment[0] {
+		case ':':
+			name, _ := splitParam(segment)
+			value, ok := params[name]
+			if !ok || value == "" {
+				return "", fmt.Errorf("%w: %s in %s", ErrMissingParam, name, path)
+			}
+			segments[i] = url.PathEscape(value)
+		case '*':
+			name, _ := splitParam(segment)
+			value, ok := params[name]
+			if !ok {
+				return "", fmt.Errorf("%w: %s in %s", ErrMissingParam, name, path)
+			}
+			parts := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j, part := range parts {
+				parts[j] = url.PathEscape(part)
+			}
+			segments[i] = strings.Join(parts, "/")
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// paramNames returns the names of the parameters in an httprouter path
+// pattern in order of appearance.
+func paramNames(path string) []string {
+	var names []string
+	for segment := range strings.SplitSeq(path, "/") {
+		if isParam(segment) || isCatchAll(segment) {
+			name, _ := splitParam(segment)
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// splitParam splits a parameter segment such as ":id|int" into the name and
+// the constraint of the parameter.
+func splitParam(segment string) (name, constraint string) {
+	name, constraint, _ = strings.Cut(segment[1:], "|")
+	return name, constraint
+}
+
+// stripConstraints removes the parameter constraints from a path pattern,
+// leaving a plain httprouter path.
+func stripConstraints(path string) string {
+	if !strings.Contains(path, "|") {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isParam(segment) || isCatchAll(segment) {
+			name, _ := splitParam(segment)
+			segments[i] = segment[:1] + name
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isParam reports whether a path segment is a named parameter.
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, ":")
+}
+
+// isCatchAll reports whether a path segment is a catch-all parameter.
+func isCatchAll(segment string) bool {
+	return strings.HasPrefix(segment, "*")
+}
+
+// anonymousParams replaces the parameter names of an httprouter path with
+// placeholders.
+func anonymousParams(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case isParam(segment):
+			segments[i] = ":"
+		case isCatchAll(segment):
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
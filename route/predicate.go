@@ -0,0 +1,180 @@
+package route
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// Predicate is a condition a request must fulfil to be handled by a route,
+// see [Route.When].
+type Predicate interface {
+	// Match reports whether the request fulfils the predicate.
+	Match(r *http.Request) bool
+	// String describes the predicate, e.g. "header X-Api-Version=2".
+	String() string
+}
+
+// When restricts the route and its children to requests fulfilling all of
+// the predicates. Predicates are inherited by the route's children.
+//
+// Routes with the same method and path are tried in the order they are
+// declared, until the predicates of one of them match. If none matches,
+// [ErrUnsupportedMediaType] is sent if the content type of the request is the
+// reason for each of them, [ErrNotAcceptable] otherwise. A route without
+// predicates matches every request, so it must be declared last.
+func (r *Route) When(predicates ...Predicate) *Route {
+	r.predicates = append(r.predicates, predicates...)
+	return r
+}
+
+// Header matches requests with the given header value. An empty value
+// matches requests having the header at all.
+func Header(name, value string) Predicate {
+	return predicate{
+		desc: "header " + name + "=" + value,
+		match: func(r *http.Request) bool {
+			values := r.Header.Values(name)
+			if value == "" {
+				return len(values) > 0
+			}
+			return slices.Contains(values, value)
+		},
+	}
+}
+
+// Query matches requests with the given query parameter value. An empty value
+// matches requests having the parameter at all.
+func Query(name, value string) Predicate {
+	return predicate{
+		desc: "query " + name + "=" + value,
+		match: func(r *http.Request) bool {
+			values, ok := r.URL.Query()[name]
+			if value == "" {
+				return ok
+			}
+			return slices.Contains(values, value)
+		},
+	}
+}
+
+// ContentType matches requests whose body has one of the given media types,
+// ignoring parameters like the charset. A type such as "image/*" matches all
+// of its subtypes.
+func ContentType(types ...string) Predicate {
+	return predicate{
+		desc:      "content-type " + strings.Join(types, ","),
+		mediaType: true,
+		match: func(r *http.Request) bool {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil {
+				return false
+			}
+			return slices.ContainsFunc(types, func(t string) bool {
+				if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+					return strings.HasPrefix(mediaType, strings.ToLower(prefix)+"/")
+				}
+				return mediaType == strings.ToLower(t)
+			})
+		},
+	}
+}
+
+// predicate is a [Predicate] created by this package.
+type predicate struct {
+	desc  string
+	match func(*http.Request) bool
+	// mediaType reports whether the predicate checks the content type, so
+	// failing it means the media type is unsupported.
+	mediaType bool
+}
+
+// Match reports whether the request fulfils the predicate.
+func (p predicate) Match(r *http.Request) bool {
+	return p.match(r)
+}
+
+// String describes the predicate.
+func (p predicate) String() string {
+	return p.desc
+}
+
+// predicateRoutes replaces the routes sharing a method and path with at least
+// one of them having predicates by a single route trying them in order.
+func predicateRoutes(routes []Info) ([]Info, error) {
+	type location struct{ method, host, path string }
+
+	candidates := make(map[location][]Info)
+	for _, info := range routes {
+		loc := location{info.Method, info.Host, info.Path}
+		candidates[loc] = append(candidates[loc], info)
+	}
+
+	merged := make(map[location]bool)
+	result := make([]Info, 0, len(routes))
+	for _, info := range routes {
+		loc := location{info.Method, info.Host, info.Path}
+		if merged[loc] {
+			continue
+		}
+		group := candidates[loc]
+		if !slices.ContainsFunc(group, func(i Info) bool { return len(i.predicates) > 0 }) {
+			result = append(result, info)
+			continue
+		}
+		merged[loc] = true
+
+		for i, c := range group[:len(group)-1] {
+			if len(c.predicates) == 0 {
+				return nil, fmt.Errorf("%w: route without predicates shadows the next one: %s and %s", ErrConflict, describe(c), describe(group[i+1]))
+			}
+		}
+
+		route := group[0]
+		route.Name = ""
+		route.Predicates = nil
+		route.predicates = nil
+		route.handler = nil
+		route.candidates = group
+		result = append(result, route)
+	}
+
+	return result, nil
+}
+
+// choose returns a handler that dispatches to the first of the routes whose
+// predicates match the request.
+func (r *Router) choose(candidates []Info) (http.Handler, error) {
+	handlers := make([]http.Handler, len(candidates))
+	for i, info := range candidates {
+		handler, err := r.compose(info)
+		if err != nil {
+			return nil, err
+		}
+		handlers[i] = handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		unsupported := true
+		for i, info := range candidates {
+			failed := slices.IndexFunc(info.predicates, func(p Predicate) bool { return !p.Match(req) })
+			if failed < 0 {
+				handlers[i].ServeHTTP(w, req)
+				return
+			}
+			if p, ok := info.predicates[failed].(predicate); !ok || !p.mediaType {
+				unsupported = false
+			}
+		}
+
+		if unsupported {
+			_ = goweb.RespondError(w, req, ErrUnsupportedMediaType)
+			return
+		}
+		_ = goweb.RespondError(w, req, ErrNotAcceptable)
+	}), nil
+}
@@ -0,0 +1,178 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// Renderer writes a listing of routes, as returned by [Route.Routes], in a
+// specific format.
+type Renderer interface {
+	// ContentType returns the media type of the rendered listing.
+	ContentType() string
+	// Render writes the listing of the routes to w.
+	Render(w io.Writer, routes []Info) error
+}
+
+// Description sets a human readable description of the route. It is not
+// inherited by the route's children.
+func (r *Route) Description(text string) *Route {
+	r.description = text
+	return r
+}
+
+// Render writes a listing of the route and its children using the renderer.
+func (r *Route) Render(w io.Writer, renderer Renderer) error {
+	routes, err := r.Routes()
+	if err != nil {
+		return err
+	}
+	return renderer.Render(w, routes)
+}
+
+// RoutesHandler serves a listing of the route and its children using the
+// renderer, e.g. on an internal admin endpoint.
+func RoutesHandler(r *Route, renderer Renderer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var buf bytes.Buffer
+		if err := r.Render(&buf, renderer); err != nil {
+			_ = goweb.RespondError(w, req, goweb.ErrGeneric.Wrap(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", renderer.ContentType())
+		_, _ = buf.WriteTo(w)
+	})
+}
+
+// TreeRenderer renders routes as an indented tree of their groups. Each
+// route is listed with its name and effective middleware.
+type TreeRenderer struct {
+	// Indent is the indentation per level, two spaces by default.
+	Indent string
+}
+
+// ContentType returns the media type of the tree.
+func (TreeRenderer) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Render writes the tree of the routes to w.
+func (t TreeRenderer) Render(w io.Writer, routes []Info) error {
+	indent := t.Indent
+	if indent == "" {
+		indent = "  "
+	}
+
+	var b strings.Builder
+	var groups []GroupInfo
+	for _, info := range routes {
+		// Open the groups the route doesn't share with the previous one
+		common := 0
+		for common < min(len(groups), len(info.Groups)) && groups[common] == info.Groups[common] {
+			common++
+		}
+		for depth, group := range info.Groups[common:] {
+			b.WriteString(strings.Repeat(indent, common+depth))
+			b.WriteString(group.Path)
+			if group.Name != "" {
+				b.WriteString(" (" + group.Name + ")")
+			}
+			b.WriteString("\n")
+		}
+		groups = info.Groups
+
+		b.WriteString(strings.Repeat(indent, len(info.Groups)))
+		fmt.Fprintf(&b, "%s %s%s", info.Method, info.Host, info.Path)
+		if info.Name != "" {
+			b.WriteString(" (" + info.Name + ")")
+		}
+		if len(info.Middleware) > 0 {
+			names := make([]string, 0, len(info.Middleware))
+			for _, mw := range info.Middleware {
+				names = append(names, mw.Name)
+			}
+			b.WriteString(" [" + strings.Join(names, ", ") + "]")
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// JSONRenderer renders routes as an indented JSON array of [Info].
+type JSONRenderer struct{}
+
+// ContentType returns the media type of JSON.
+func (JSONRenderer) ContentType() string {
+	return "application/json"
+}
+
+// Render writes the routes as JSON to w.
+func (JSONRenderer) Render(w io.Writer, routes []Info) error {
+	if routes == nil {
+		routes = []Info{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(routes)
+}
+
+// MarkdownRenderer renders routes as a Markdown table with their method,
+// path, name, authorization requirements and description.
+type MarkdownRenderer struct{}
+
+// ContentType returns the media type of Markdown.
+func (MarkdownRenderer) ContentType() string {
+	return "text/markdown; charset=utf-8"
+}
+
+// Render writes the Markdown table of the routes to w.
+func (MarkdownRenderer) Render(w io.Writer, routes []Info) error {
+	var b strings.Builder
+	b.WriteString("| Method | Path | Name | Auth | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, info := range routes {
+		cells := []string{
+			info.Method,
+			"`" + info.Host + info.Path + "`",
+			info.Name,
+			auth(info),
+			info.Description,
+		}
+		for i, cell := range cells {
+			cells[i] = markdownEscaper.Replace(cell)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscaper escapes text for a cell of a Markdown table.
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\n", " ")
+
+// auth describes the authorization requirements of a route.
+func auth(info Info) string {
+	if info.Public() {
+		return "public"
+	}
+
+	var parts []string
+	if len(info.Scopes) > 0 {
+		parts = append(parts, "scopes: "+strings.Join(info.Scopes, ", "))
+	}
+	if len(info.Roles) > 0 {
+		parts = append(parts, "roles: "+strings.Join(info.Roles, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
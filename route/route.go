@@ -3,24 +3,40 @@ package route
 import (
 	"fmt"
 	"net/http"
-	"net/url"
-
-	"github.com/julienschmidt/httprouter"
+	"strings"
+	"time"
 )
 
 // Route is an HTTP Route with optional children.
 type Route struct {
-	method     string
-	path       string
-	handler    http.Handler
-	children   []*Route
-	middleware []Middleware
+	methods     []string
+	path        string
+	host        string
+	name        string
+	handler     http.Handler
+	children    []*Route
+	middleware  []Middleware
+	meta        map[string]any
+	tags        []string
+	without     []string
+	version     string
+	deprecated  bool
+	sunset      time.Time
+	timeout     time.Duration
+	maxBody     int64
+	scopes      []string
+	roles       []string
+	description string
+	predicates  []Predicate
+	// err is an error that occurred while constructing the route. It is
+	// returned when the tree is walked.
+	err error
 }
 
 // Handler creates a simple route from an [http.Handler].
 func Handler(method, path string, handler http.Handler) *Route {
 	return &Route{
-		method:  method,
+		methods: []string{method},
 		path:    path,
 		handler: handler,
 	}
@@ -28,10 +44,23 @@ func Handler(method, path string, handler http.Handler) *Route {
 
 // Func creates a simple route from a handler function
 func Func(method, path string, f http.HandlerFunc) *Route {
+	r := Route{
+		methods: []string{method},
+		path:    path,
+	}
+	// A nil function must not end up as a non-nil handler.
+	if f != nil {
+		r.handler = f
+	}
+	return &r
+}
+
+// Methods creates a route that serves several methods from one handler.
+func Methods(methods []string, path string, handler http.Handler) *Route {
 	return &Route{
-		method:  method,
+		methods: methods,
 		path:    path,
-		handler: f,
+		handler: handler,
 	}
 }
 
@@ -57,69 +86,135 @@ func (r *Route) Middleware(mw ...Middleware) *Route {
 	return r
 }
 
+// Without removes the middleware with the given names, inherited from the
+// route's ancestors, from the route and its children. See [Named] for naming
+// middleware.
+func (r *Route) Without(names ...string) *Route {
+	r.without = append(r.without, names...)
+	return r
+}
+
+// Name sets the name of the route. Named routes can be turned back into URLs
+// using [Router.URL].
+func (r *Route) Name(name string) *Route {
+	r.name = name
+	return r
+}
+
+// Meta attaches a metadata value to the route. Metadata is inherited by the
+// route's children, which can override it.
+func (r *Route) Meta(key string, value any) *Route {
+	if r.meta == nil {
+		r.meta = make(map[string]any)
+	}
+	r.meta[key] = value
+	return r
+}
+
+// Tag adds tags to the route. Tags are inherited by the route's children.
+func (r *Route) Tag(tags ...string) *Route {
+	r.tags = append(r.tags, tags...)
+	return r
+}
+
 // Build the route into an HTTP handler.
-func (r *Route) Build() (*httprouter.Router, error) {
-	router := httprouter.New()
-	err := r.register(router, "/", nil)
+func (r *Route) Build(opts ...Option) (*Router, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	routes, err := r.Routes()
 	if err != nil {
 		return nil, err
 	}
-	return router, nil
-}
 
-// register the route and all its children.
-func (r *Route) register(router *httprouter.Router, prefix string, mw []Middleware) error {
-	// Prepend the parent path prefix
-	path, err := url.JoinPath(prefix, r.path)
+	if o.authorizer == nil {
+		for _, info := range routes {
+			if !info.Public() {
+				return nil, fmt.Errorf("%s: %w", describe(info), ErrNoAuthorizer)
+			}
+		}
+	}
+
+	routes, err = predicateRoutes(routes)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Prepend the parent's middleware
-	mw = append(mw, r.middleware...)
+	if o.versioning != nil {
+		routes = versionRoutes(routes)
+	}
 
-	// Register the route handler if it has one.
-	if r.handler != nil {
-		handler := r.handler
-		for i := len(mw) - 1; i >= 0; i-- {
-			handler = mw[i].Handler(handler)
+	if o.autoMethods {
+		routes = autoMethods(routes)
+	}
+
+	router := newRouter(o)
+	if checker, ok := router.backend.(ConflictChecker); ok {
+		if err := checkConflicts(routes, checker); err != nil {
+			return nil, err
 		}
-		router.Handler(r.method, path, handler)
 	}
 
-	// Recursively register the route's children.
-	for _, child := range r.children {
-		err := child.register(router, path, mw)
-		if err != nil {
-			return err
+	for _, info := range routes {
+		if err := router.register(info); err != nil {
+			return nil, err
 		}
 	}
 
-	return nil
+	return router, nil
 }
 
-// Dump returns string representations of the route and its children.
+// Dump returns string representations of the route and its children,
+// followed by the names of their effective middleware, their required scopes
+// and roles, their predicates, their version and deprecation, if any.
+//
+// If the tree is invalid, the listing stops at the invalid route and ends
+// with an "error: ..." line. Use [Route.Routes] to handle the error.
 func (r *Route) Dump() []string {
-	return r.dump("/")
-}
+	infos, err := r.Routes()
 
-// dump returns string representations of the route and its children.
-func (r *Route) dump(prefix string) []string {
-	path, _ := url.JoinPath(prefix, r.path)
-
-	var routes []string
-	if r.handler != nil {
-		routes = append(routes, fmt.Sprintf("%s %s", r.method, path))
+	routes := make([]string, 0, len(infos)+1)
+	for _, info := range infos {
+		route := fmt.Sprintf("%s %s%s", info.Method, info.Host, info.Path)
+		if len(info.Middleware) > 0 {
+			names := make([]string, 0, len(info.Middleware))
+			for _, mw := range info.Middleware {
+				names = append(names, mw.Name)
+			}
+			route += " [" + strings.Join(names, ", ") + "]"
+		}
+		if len(info.Scopes) > 0 {
+			route += " scopes=" + strings.Join(info.Scopes, ",")
+		}
+		if len(info.Roles) > 0 {
+			route += " roles=" + strings.Join(info.Roles, ",")
+		}
+		if len(info.Predicates) > 0 {
+			route += " when=" + strings.Join(info.Predicates, ";")
+		}
+		if info.Version != "" {
+			route += " version=" + info.Version
+		}
+		if info.Deprecated {
+			route += " deprecated"
+		}
+		if !info.Sunset.IsZero() {
+			route += " sunset=" + info.Sunset.Format(time.DateOnly)
+		}
+		routes = append(routes, route)
 	}
 
-	for _, child := range r.children {
-		routes = append(routes, child.dump(path)...)
+	if err != nil {
+		routes = append(routes, "error: "+err.Error())
 	}
 
 	return routes
 }
 
-// Middleware is the interface for an HTTP middleware
+// Middleware is the interface for an HTTP middleware. Middleware that also
+// implements [RouteMiddleware] is built with the info of the route it wraps.
 type Middleware interface {
 	Handler(http.Handler) http.Handler
 }
@@ -131,3 +226,38 @@ type MiddlewareFunc func(http.Handler) http.Handler
 func (f MiddlewareFunc) Handler(h http.Handler) http.Handler {
 	return f(h)
 }
+
+// RouteMiddleware is implemented by middleware that wants to know which route
+// it wraps. When a route is built, ForRoute is called once per route and
+// method, instead of [Middleware.Handler], with the route's [Info]. This
+// allows middleware to prepare per-route state, like metric instruments or
+// rate-limit buckets, without looking up the route on every request.
+type RouteMiddleware interface {
+	ForRoute(info Info, next http.Handler) http.Handler
+}
+
+// RouteMiddlewareFunc wraps a function to satisfy both the [Middleware] and
+// the [RouteMiddleware] interface.
+type RouteMiddlewareFunc func(Info, http.Handler) http.Handler
+
+// ForRoute returns the middleware's handler for the given route.
+func (f RouteMiddlewareFunc) ForRoute(info Info, next http.Handler) http.Handler {
+	return f(info, next)
+}
+
+// Handler returns the middleware's handler. Outside of a [Router], it takes
+// the route info from the request context on every request, if available.
+func (f RouteMiddlewareFunc) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, _ := FromContext(r.Context())
+		f(info, next).ServeHTTP(w, r)
+	})
+}
+
+// wrap applies the middleware to the handler of the given route.
+func wrap(mw Middleware, info Info, next http.Handler) http.Handler {
+	if rm, ok := mw.(RouteMiddleware); ok {
+		return rm.ForRoute(info, next)
+	}
+	return mw.Handler(next)
+}
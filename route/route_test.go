@@ -0,0 +1,673 @@
+package route_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sehrgutesoftware/goweb"
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func noop(w http.ResponseWriter, r *http.Request) {}
+
+func TestItGeneratesURLsForNamedRoutes(t *testing.T) {
+	router, err := route.Prefix("/api", route.Group("/users", []*route.Route{
+		route.Func("GET", "/:id", noop).Name("user.show"),
+		route.Func("GET", "/:id/files/*path", noop).Name("user.file"),
+	})).Build()
+	assert.NoError(t, err)
+
+	url, err := router.URL("user.show", map[string]string{"id": "42"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/users/42", url)
+
+	// Parameter values are escaped
+	url, err = router.URL("user.show", map[string]string{"id": "a b/c"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/users/a%20b%2Fc", url)
+
+	// Catch-all parameters keep their slashes
+	url, err = router.URL("user.file", map[string]string{"id": "42", "path": "/docs/my file.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/users/42/files/docs/my%20file.txt", url)
+
+	_, err = router.URL("user.show", nil)
+	assert.ErrorIs(t, err, route.ErrMissingParam)
+
+	_, err = router.URL("user.delete", nil)
+	assert.ErrorIs(t, err, route.ErrUnknownName)
+}
+
+func TestItRejectsDuplicateRouteNames(t *testing.T) {
+	_, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/a", noop).Name("same"),
+		route.Func("GET", "/b", noop).Name("same"),
+	}).Build()
+	assert.ErrorIs(t, err, route.ErrDuplicateName)
+}
+
+func TestItAcceptsNamesOfRoutesWithSeveralMethods(t *testing.T) {
+	router, err := route.Group("/", []*route.Route{
+		route.Methods([]string{"GET", "POST"}, "/x", http.HandlerFunc(noop)).Name("x"),
+		route.Static("/app", fstest.MapFS{"index.html": {}}, route.StaticOptions{}).Name("app"),
+	}).Build()
+	assert.NoError(t, err)
+
+	url, err := router.URL("x", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/x", url)
+
+	url, err = router.URL("app", map[string]string{"filepath": "/index.html"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/app/index.html", url)
+}
+
+func TestItDescribesTheRouteTree(t *testing.T) {
+	auth := route.Named("auth", route.MiddlewareFunc(func(h http.Handler) http.Handler { return h }))
+
+	routes, err := route.Group("/api", []*route.Route{
+		route.Group("/users", []*route.Route{
+			route.Func("GET", "/:id", noop).Name("user.show").Meta("owner", "accounts"),
+		}).Name("users").Middleware(auth),
+	}).Meta("owner", "platform").Routes()
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+
+	info := routes[0]
+	assert.Equal(t, "GET", info.Method)
+	assert.Equal(t, "/api/users/:id", info.Path)
+	assert.Equal(t, "user.show", info.Name)
+	assert.Equal(t, []string{"id"}, info.Params)
+	assert.Equal(t, map[string]any{"owner": "accounts"}, info.Meta)
+	assert.Equal(t, []route.GroupInfo{{Path: "/api"}, {Path: "/api/users", Name: "users"}}, info.Groups)
+	assert.Len(t, info.Middleware, 1)
+	assert.Equal(t, "auth", info.Middleware[0].Name)
+}
+
+func TestItServesSeveralMethodsFromOneRoute(t *testing.T) {
+	router, err := route.Group("/", []*route.Route{
+		route.Methods([]string{"PUT", "PATCH"}, "/users/:id", http.HandlerFunc(noop)),
+		route.Func("GET", "/users/:id", noop),
+	}).Build(route.WithAutoMethods())
+	assert.NoError(t, err)
+
+	for _, method := range []string{"PUT", "PATCH", "GET", "HEAD"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/users/1", nil))
+		assert.Equal(t, http.StatusOK, w.Code, method)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/users/1", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, PATCH, PUT", w.Header().Get("Allow"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("DELETE", "/users/1", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, OPTIONS, PATCH, PUT", w.Header().Get("Allow"))
+}
+
+func TestItRejectsConflictingRoutes(t *testing.T) {
+	for _, paths := range [][2]string{
+		{"/users/:id", "/users/:name"},
+		{"/users/:id", "/users/me"},
+		{"/users/:id", "/users/:id"},
+		{"/files/", "/files/*path"},
+	} {
+		_, err := route.Group("/api", []*route.Route{
+			route.Group("/", []*route.Route{route.Func("GET", paths[0], noop)}).Name("a"),
+			route.Group("/", []*route.Route{route.Func("GET", paths[1], noop)}).Name("b"),
+		}).Build()
+		assert.ErrorIs(t, err, route.ErrConflict, paths)
+		assert.ErrorContains(t, err, "/api > a")
+		assert.ErrorContains(t, err, "/api > b")
+	}
+
+	_, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/users/:id", noop),
+		route.Func("GET", "/users/:id/posts", noop),
+		route.Func("POST", "/users/:name", noop),
+	}).Build()
+	assert.NoError(t, err)
+}
+
+func TestItDispatchesOnTheRequestHost(t *testing.T) {
+	respond := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			params := httprouter.ParamsFromContext(r.Context())
+			w.Write([]byte(body + params.ByName("tenantID") + params.ByName("id")))
+		}
+	}
+
+	router, err := route.Group("/", []*route.Route{
+		route.Host("admin.example.com", []*route.Route{
+			route.Func("GET", "/users/:id", respond("admin")),
+		}),
+		route.Host("{tenantID}.Example.com", []*route.Route{
+			route.Func("GET", "/users/:id", respond("tenant")),
+		}),
+		route.Func("GET", "/users/:id", respond("default")),
+	}).Build()
+	assert.NoError(t, err)
+
+	for host, body := range map[string]string{
+		"admin.example.com":      "admin1",
+		"ADMIN.example.com:8080": "admin1",
+		"acme.example.com":       "tenantacme1",
+		"example.com":            "default1",
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/users/1", nil)
+		r.Host = host
+		router.ServeHTTP(w, r)
+		assert.Equal(t, body, w.Body.String(), host)
+	}
+}
+
+func TestItMountsForeignHandlers(t *testing.T) {
+	var calls int
+	counter := route.MiddlewareFunc(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	router, err := route.Group("/api", []*route.Route{
+		route.Mount("/admin", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Method + " " + r.URL.Path))
+		})),
+	}).Middleware(counter).Build()
+	assert.NoError(t, err)
+
+	for _, method := range []string{"GET", "POST", "DELETE"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/api/admin/users/1", nil))
+		assert.Equal(t, method+" /users/1", w.Body.String())
+	}
+	assert.Equal(t, 3, calls)
+}
+
+func TestItParsesTypedPathParams(t *testing.T) {
+	var id int64
+	var paramErr error
+	router, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/users/:id", func(w http.ResponseWriter, r *http.Request) {
+			id, paramErr = route.Param[int64](r, "id")
+		}),
+		route.Func("GET", "/posts/:id|int", noop),
+	}).Build()
+	assert.NoError(t, err)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/42", nil))
+	assert.NoError(t, paramErr)
+	assert.Equal(t, int64(42), id)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/abc", nil))
+	assert.ErrorIs(t, paramErr, route.ErrInvalidPathParam)
+	assert.Equal(t, map[string]any{"param": "id"}, paramErr.(goweb.APIError).ErrorDetail())
+	assert.EqualError(t, paramErr, "invalid path parameter")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts/42", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/posts/abc", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	_, err = route.Func("GET", "/posts/:id|bogus", noop).Build()
+	assert.ErrorIs(t, err, route.ErrUnknownConstraint)
+}
+
+func TestItRespondsWithJSONErrors(t *testing.T) {
+	router, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/users", noop),
+		route.Func("GET", "/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") }),
+	}).Build(route.WithJSONErrors())
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/nope", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"not_found","message":"not found","detail":null}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/users", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, OPTIONS", w.Header().Get("Allow"))
+	assert.JSONEq(t, `{"code":"method_not_allowed","message":"method not allowed","detail":null}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"code":"generic","message":"","detail":null}`, w.Body.String())
+}
+
+func TestItExposesTheMatchedRouteInTheContext(t *testing.T) {
+	var pattern string
+	var tags []string
+	var owner any
+	metrics := route.MiddlewareFunc(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info, ok := route.FromContext(r.Context())
+			assert.True(t, ok)
+			pattern, tags, owner = info.Path, info.Tags, info.Meta["owner"]
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	router, err := route.Group("/invoices", []*route.Route{
+		route.Func("GET", "/:id", noop).Tag("read"),
+	}).Tag("billing").Meta("owner", "finance").Middleware(metrics).Build()
+	assert.NoError(t, err)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/invoices/42", nil))
+	assert.Equal(t, "/invoices/:id", pattern)
+	assert.Equal(t, []string{"billing", "read"}, tags)
+	assert.Equal(t, "finance", owner)
+}
+
+func TestItExcludesInheritedMiddleware(t *testing.T) {
+	pass := func(next http.Handler) http.Handler { return next }
+	auth := route.Named("auth", route.MiddlewareFunc(pass))
+	logging := route.Named("logging", route.MiddlewareFunc(pass))
+
+	tree := route.Group("/api", []*route.Route{
+		route.Func("GET", "/health", noop).Without("auth"),
+		route.Func("GET", "/users", noop),
+	}).Middleware(logging, auth)
+
+	assert.Equal(t, []string{
+		"GET /api/health [logging]",
+		"GET /api/users [logging, auth]",
+	}, tree.Dump())
+}
+
+func TestItSwapsRoutersAtomically(t *testing.T) {
+	swappable, err := route.NewSwappable(route.Func("GET", "/old", noop))
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	swappable.ServeHTTP(w, httptest.NewRequest("GET", "/old", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// A failing build keeps the current router
+	err = swappable.Swap(route.Group("/", []*route.Route{
+		route.Func("GET", "/:a", noop),
+		route.Func("GET", "/:b", noop),
+	}))
+	assert.ErrorIs(t, err, route.ErrConflict)
+
+	w = httptest.NewRecorder()
+	swappable.ServeHTTP(w, httptest.NewRequest("GET", "/old", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, swappable.Swap(route.Func("GET", "/new", noop)))
+
+	w = httptest.NewRecorder()
+	swappable.ServeHTTP(w, httptest.NewRequest("GET", "/old", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestItEnforcesTimeoutsAndBodyLimits(t *testing.T) {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte("done"))
+	}
+
+	router, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/slow", slow),
+		route.Func("GET", "/slow/unlimited", slow).Timeout(-1),
+		route.Func("POST", "/upload", noop),
+		route.Func("POST", "/upload/large", noop).MaxBody(1024),
+	}).Timeout(10 * time.Millisecond).MaxBody(4).Build()
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"timeout"`)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/slow/unlimited", nil)
+	ctx, cancel := context.WithTimeout(r.Context(), 50*time.Millisecond)
+	defer cancel()
+	router.ServeHTTP(w, r.WithContext(ctx))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/upload", strings.NewReader("too large")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"body_too_large"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/upload/large", strings.NewReader("not too large")))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestItBuildsRouteMiddlewareOncePerRoute(t *testing.T) {
+	var built []string
+	metrics := route.Named("metrics", route.RouteMiddlewareFunc(func(info route.Info, next http.Handler) http.Handler {
+		built = append(built, info.Method+" "+info.Path)
+		label := info.Meta["owner"].(string)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Owner", label)
+			next.ServeHTTP(w, r)
+		})
+	}))
+
+	router, err := route.Group("/invoices", []*route.Route{
+		route.Methods([]string{"GET", "POST"}, "", http.HandlerFunc(noop)),
+		route.Func("GET", "/:id", noop).Meta("owner", "billing"),
+	}).Meta("owner", "finance").Middleware(metrics).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GET /invoices", "POST /invoices", "GET /invoices/:id"}, built)
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/invoices/42", nil))
+		assert.Equal(t, "billing", w.Header().Get("X-Owner"))
+	}
+	assert.Len(t, built, 3)
+}
+
+type principal struct{ scopes, roles []string }
+
+func (p principal) HasScope(scope string) bool { return slices.Contains(p.scopes, scope) }
+func (p principal) HasRole(role string) bool   { return slices.Contains(p.roles, role) }
+
+type principalKey struct{}
+
+func TestItAuthorizesRoutes(t *testing.T) {
+	tree := route.Group("/orders", []*route.Route{
+		route.Func("GET", "", noop),
+		route.Func("POST", "", noop).Require("orders:write"),
+		route.Func("DELETE", "/:id", noop).Roles("admin", "support"),
+		route.Func("GET", "/health", noop),
+	}).Require("orders:read")
+	tree = route.Group("/", []*route.Route{tree, route.Func("GET", "/status", noop)})
+
+	assert.Equal(t, []string{
+		"GET /orders scopes=orders:read",
+		"POST /orders scopes=orders:read,orders:write",
+		"DELETE /orders/:id scopes=orders:read roles=admin,support",
+		"GET /orders/health scopes=orders:read",
+		"GET /status",
+	}, tree.Dump())
+
+	_, err := tree.Build()
+	assert.ErrorIs(t, err, route.ErrNoAuthorizer)
+
+	router, err := tree.Build(route.WithAuthorizer(route.AuthorizerFunc(func(ctx context.Context) (route.Principal, bool) {
+		p, ok := ctx.Value(principalKey{}).(principal)
+		return p, ok
+	})))
+	assert.NoError(t, err)
+
+	reader := principal{scopes: []string{"orders:read"}}
+	support := principal{scopes: []string{"orders:read"}, roles: []string{"support"}}
+	tests := []struct {
+		method, path string
+		principal    *principal
+		status       int
+	}{
+		{"GET", "/status", nil, http.StatusOK},
+		{"GET", "/orders", nil, http.StatusUnauthorized},
+		{"GET", "/orders", &reader, http.StatusOK},
+		{"POST", "/orders", &reader, http.StatusForbidden},
+		{"DELETE", "/orders/42", &reader, http.StatusForbidden},
+		{"DELETE", "/orders/42", &support, http.StatusOK},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.principal != nil {
+			r = r.WithContext(context.WithValue(r.Context(), principalKey{}, *tt.principal))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tt.status, w.Code, "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestItLintsRouteTrees(t *testing.T) {
+	auth := route.Named("auth", route.MiddlewareFunc(func(h http.Handler) http.Handler { return h }))
+
+	warnings, err := route.Group("/api", []*route.Route{
+		route.Group("/empty", nil),
+		route.Handler("GET", "/nil", nil),
+		route.Func("POST", "/nilfunc", nil),
+		route.Methods(nil, "/nomethod", http.HandlerFunc(noop)),
+		route.Func("GET", "/users/:id", noop),
+		route.Func("GET", "/users/:user_id/posts", noop),
+		route.Func("GET", "/files/", noop).Middleware(auth),
+	}).Middleware(auth).Lint()
+	assert.NoError(t, err)
+
+	lines := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		lines = append(lines, w.String())
+	}
+	assert.Equal(t, []string{
+		"empty_group /api/empty",
+		"missing_handler /api/nil GET",
+		"missing_handler /api/nilfunc POST",
+		"missing_method /api/nomethod",
+		"trailing_slash GET /api/files/ has a trailing slash",
+		"param_names /api/users/:user_id/posts :user_id != :id",
+		"duplicate_middleware GET /api/files/ auth",
+	}, lines)
+
+	_, err = route.Handler("", "/empty", http.HandlerFunc(noop)).Lint()
+	assert.ErrorIs(t, err, route.ErrEmptyMethod)
+
+	_, err = route.Handler("", "/empty", http.HandlerFunc(noop)).Build()
+	assert.ErrorIs(t, err, route.ErrEmptyMethod)
+}
+
+func TestItRendersRouteListings(t *testing.T) {
+	auth := route.Named("auth", route.MiddlewareFunc(func(h http.Handler) http.Handler { return h }))
+
+	users := route.Group("/users", []*route.Route{
+		route.Func("GET", "/:id|int", noop).Name("user.show").Description("Shows a user"),
+		route.Func("DELETE", "/:id|int", noop).Roles("admin"),
+	}).Name("users").Require("users:read").Middleware(auth)
+	tree := route.Group("/", []*route.Route{
+		users,
+		route.Handler("GET", "/internal/routes", route.RoutesHandler(users, route.JSONRenderer{})),
+	})
+
+	var b strings.Builder
+	assert.NoError(t, tree.Render(&b, route.TreeRenderer{}))
+	assert.Equal(t, `/
+  /users (users)
+    GET /users/:id|int (user.show) [auth]
+    DELETE /users/:id|int [auth]
+  GET /internal/routes
+`, b.String())
+
+	b.Reset()
+	assert.NoError(t, tree.Render(&b, route.MarkdownRenderer{}))
+	assert.Equal(t, "| Method | Path | Name | Auth | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| GET | `/users/:id\\|int` | user.show | scopes: users:read | Shows a user |\n"+
+		"| DELETE | `/users/:id\\|int` |  | scopes: users:read; roles: admin |  |\n"+
+		"| GET | `/internal/routes` |  | public |  |\n", b.String())
+
+	w := httptest.NewRecorder()
+	route.RoutesHandler(users, route.JSONRenderer{}).ServeHTTP(w, httptest.NewRequest("GET", "/internal/routes", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), `"description": "Shows a user"`)
+}
+
+func TestItMatchesRoutesByPredicates(t *testing.T) {
+	echo := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name))
+		}
+	}
+
+	tree := route.Group("/reports", []*route.Route{
+		route.Func("GET", "", echo("v2")).When(route.Header("X-Api-Version", "2")),
+		route.Func("GET", "", echo("csv")).When(route.Query("format", "csv")),
+		route.Func("GET", "", echo("default")).Name("reports"),
+		route.Func("POST", "", echo("upload")).When(route.ContentType("multipart/form-data")),
+		route.Func("POST", "", echo("json")).When(route.ContentType("application/json")),
+		route.Func("PUT", "", echo("image")).When(route.ContentType("image/*"), route.Header("X-Owner", "")),
+	})
+	assert.Contains(t, tree.Dump(), "GET /reports when=header X-Api-Version=2")
+
+	for _, backend := range []route.NewBackend{route.NewHTTPRouterBackend, route.NewServeMuxBackend, route.NewTreeBackend} {
+		router, err := tree.Build(route.WithBackend(backend))
+		assert.NoError(t, err)
+
+		url, err := router.URL("reports", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "/reports", url)
+
+		for _, tc := range []struct {
+			method, path string
+			header       http.Header
+			status       int
+			body         string
+		}{
+			{"GET", "/reports", nil, 200, "default"},
+			{"GET", "/reports?format=csv", nil, 200, "csv"},
+			{"GET", "/reports?format=csv", http.Header{"X-Api-Version": {"2"}}, 200, "v2"},
+			{"POST", "/reports", http.Header{"Content-Type": {"multipart/form-data; boundary=x"}}, 200, "upload"},
+			{"POST", "/reports", http.Header{"Content-Type": {"application/json"}}, 200, "json"},
+			{"POST", "/reports", http.Header{"Content-Type": {"text/plain"}}, 415, ""},
+			{"PUT", "/reports", http.Header{"Content-Type": {"image/png"}}, 406, ""},
+			{"PUT", "/reports", http.Header{"Content-Type": {"image/png"}, "X-Owner": {"me"}}, 200, "image"},
+		} {
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			for name, values := range tc.header {
+				r.Header[name] = values
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			assert.Equal(t, tc.status, w.Code, "%s %s %v", tc.method, tc.path, tc.header)
+			if tc.body != "" {
+				assert.Equal(t, tc.body, w.Body.String(), "%s %s %v", tc.method, tc.path, tc.header)
+			}
+		}
+	}
+
+	_, err := route.Group("/reports", []*route.Route{
+		route.Func("GET", "", noop),
+		route.Func("GET", "", noop).When(route.Query("format", "csv")),
+	}).Build()
+	assert.ErrorIs(t, err, route.ErrConflict)
+}
+
+func TestRecoverLogsPanicsOnce(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	router, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") }),
+		route.Func("GET", "/abort", func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) }),
+	}).Build(route.WithJSONErrors())
+	assert.NoError(t, err)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, 1, strings.Count(logs.String(), "level=ERROR"))
+	assert.Contains(t, logs.String(), "panic while handling GET /panic: boom")
+	assert.Contains(t, logs.String(), "TestRecoverLogsPanicsOnce")
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+	})
+}
+
+func TestItKeepsTheStackOfPanicsWithATimeout(t *testing.T) {
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	router, err := route.Group("/", []*route.Route{
+		route.Func("GET", "/panic", func(w http.ResponseWriter, r *http.Request) { panic("boom") }),
+		route.Func("GET", "/abort", func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) }),
+	}).Timeout(time.Second).Build(route.WithJSONErrors())
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, logs.String(), "panic while handling GET /panic: boom")
+	assert.Contains(t, logs.String(), "TestItKeepsTheStackOfPanicsWithATimeout.func1")
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/abort", nil))
+	})
+}
+
+func TestItRunsCommonMiddlewareOnSynthesizedOptions(t *testing.T) {
+	tagged := func(name string) route.Middleware {
+		return route.Named(name, route.MiddlewareFunc(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Middleware", name)
+				next.ServeHTTP(w, r)
+			})
+		}))
+	}
+
+	tree := route.Group("/api", []*route.Route{
+		route.Func("GET", "/x", noop),
+		route.Func("POST", "/x", noop).Middleware(tagged("audit")),
+	}).Name("api").Middleware(tagged("cors"))
+	router, err := tree.Build(route.WithAutoMethods())
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("OPTIONS", "/api/x", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"cors"}, w.Header().Values("X-Middleware"))
+
+	_, err = route.Group("/api", []*route.Route{
+		route.Func("GET", "/:id", noop),
+		route.Func("OPTIONS", "/me", noop),
+	}).Name("api").Build(route.WithAutoMethods())
+	assert.ErrorIs(t, err, route.ErrConflict)
+	assert.ErrorContains(t, err, "OPTIONS /api/:id (in api)")
+}
+
+func TestZeroSwappableIsUnavailable(t *testing.T) {
+	var swappable route.Swappable
+
+	w := httptest.NewRecorder()
+	swappable.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"unavailable"`)
+
+	assert.NoError(t, swappable.Swap(route.Func("GET", "/", noop)))
+	w = httptest.NewRecorder()
+	swappable.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestDumpReportsInvalidTrees(t *testing.T) {
+	assert.Equal(t, []string{
+		"GET /a",
+		"error: route /b: empty route method",
+	}, route.Group("/", []*route.Route{
+		route.Func("GET", "/a", noop),
+		route.Handler("", "/b", http.HandlerFunc(noop)),
+		route.Func("GET", "/c", noop),
+	}).Dump())
+}
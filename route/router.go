@@ -0,0 +1,209 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrDuplicateName is returned by [Route.Build] when two routes in the
+	// tree share the same name.
+	ErrDuplicateName = fmt.Errorf("duplicate route name")
+	// ErrUnknownName is returned by [Router.URL] when no route with the given
+	// name exists.
+	ErrUnknownName = fmt.Errorf("unknown route name")
+	// ErrMissingParam is returned by [Router.URL] when a parameter of the
+	// route's path has no value.
+	ErrMissingParam = fmt.Errorf("missing route parameter")
+	// ErrEmptyMethod is returned by [Route.Build] and [Route.Routes] when a
+	// route with a handler has an empty method.
+	ErrEmptyMethod = fmt.Errorf("empty route method")
+)
+
+// Router is the HTTP handler built from a [Route] tree.
+type Router struct {
+	// backend serves the routes without a host.
+	backend Backend
+	// hosts are the routers of the host groups, plain host names first.
+	hosts []*hostRouter
+	// names maps route names to their full path.
+	names map[string]string
+	// opts are the options the router was built with.
+	opts options
+}
+
+// newRouter creates an empty router with the given options.
+func newRouter(o options) *Router {
+	r := Router{
+		names: make(map[string]string),
+		opts:  o,
+	}
+	r.backend = r.newBackend()
+	return &r
+}
+
+// ServeHTTP dispatches the request to the backend of the matching host.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.opts.panicHandler != nil {
+		defer func() {
+			if p := recover(); p != nil {
+				r.opts.panicHandler(w, req, p)
+			}
+		}()
+	}
+
+	if len(r.hosts) > 0 {
+		host := requestHost(req)
+		for _, h := range r.hosts {
+			if params, ok := h.match(host); ok {
+				if len(params) > 0 {
+					req = req.WithContext(context.WithValue(req.Context(), hostParamsKey{}, params))
+				}
+				h.backend.ServeHTTP(w, req)
+				return
+			}
+		}
+	}
+
+	r.backend.ServeHTTP(w, req)
+}
+
+// URL returns the path of the route with the given name, filling in the
+// path parameters from params.
+//
+// Parameter values are escaped. The value of a catch-all parameter may
+// contain slashes, which are kept as segment separators.
+func (r *Router) URL(name string, params map[string]string) (string, error) {
+	path, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownName, name)
+	}
+	return expand(path, params)
+}
+
+// register adds a single route to the router.
+func (r *Router) register(info Info) error {
+	backend := r.backend
+	if info.Host != "" {
+		h, err := r.host(info.Host)
+		if err != nil {
+			return err
+		}
+		backend = h.backend
+	}
+
+	handler, err := r.compose(info)
+	if err != nil {
+		return fmt.Errorf("register %s: %w", describe(info), err)
+	}
+
+	path := info.Path
+	if !matchesConstraints(backend) {
+		path = stripConstraints(path)
+	}
+	if err := backend.Handle(info.Method, path, handler); err != nil {
+		return fmt.Errorf("register %s: %w", describe(info), err)
+	}
+
+	for _, named := range append([]Info{info}, info.candidates...) {
+		if named.Name == "" {
+			continue
+		}
+		// A route serving several methods is registered once per method.
+		if existing, ok := r.names[named.Name]; ok && existing != named.Path {
+			return fmt.Errorf("%w: %s (%s and %s)", ErrDuplicateName, named.Name, existing, named.Path)
+		}
+		r.names[named.Name] = named.Path
+	}
+
+	return nil
+}
+
+// compose builds the handler chain of a route.
+func (r *Router) compose(info Info) (http.Handler, error) {
+	if len(info.versions) > 0 {
+		return r.negotiate(info.versions)
+	}
+	if len(info.candidates) > 0 {
+		return r.choose(info.candidates)
+	}
+
+	handler := info.handler
+	if !info.Public() {
+		handler = authorize(r.opts.authorizer, info.Scopes, info.Roles, handler)
+	}
+	for i := len(info.Middleware) - 1; i >= 0; i-- {
+		handler = wrap(info.Middleware[i].Middleware, info, handler)
+	}
+	if info.MaxBody > 0 {
+		handler = limitBody(info.MaxBody, handler)
+	}
+	if info.Timeout > 0 {
+		handler = withTimeout(info.Timeout, handler)
+	}
+	if info.Deprecated {
+		handler = withDeprecation(info.Sunset, handler)
+	}
+	constraints, err := parseConstraints(info.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(constraints) > 0 && !matchesConstraints(r.backend) {
+		handler = checkConstraints(constraints, r.notFound(), handler)
+	}
+	if info.Host != "" && strings.Contains(info.Host, "{") {
+		handler = withHostParams(handler)
+	}
+	handler = withInfo(&info, handler)
+
+	return handler, nil
+}
+
+// host returns the router for the given host pattern, creating it if needed.
+func (r *Router) host(pattern string) (*hostRouter, error) {
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return h, nil
+		}
+	}
+
+	h, err := newHostRouter(pattern, r.newBackend())
+	if err != nil {
+		return nil, err
+	}
+
+	// Plain host names are matched before patterns.
+	i := len(r.hosts)
+	if !h.params {
+		i = slices.IndexFunc(r.hosts, func(h *hostRouter) bool { return h.params })
+		if i < 0 {
+			i = len(r.hosts)
+		}
+	}
+	r.hosts = slices.Insert(r.hosts, i, h)
+
+	return h, nil
+}
+
+// newBackend creates a backend configured with the router's options.
+func (r *Router) newBackend() Backend {
+	newBackend := r.opts.backend
+	if newBackend == nil {
+		newBackend = NewHTTPRouterBackend
+	}
+	return newBackend(BackendConfig{
+		NotFound:         r.opts.notFound,
+		MethodNotAllowed: r.opts.methodNotAllowed,
+	})
+}
+
+// notFound returns the handler for requests that match no route.
+func (r *Router) notFound() http.Handler {
+	if r.opts.notFound != nil {
+		return r.opts.notFound
+	}
+	return http.NotFoundHandler()
+}
@@ -0,0 +1,197 @@
+package routetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Response is the response of a request, with assertion methods. Failed
+// assertions mark the test as failed, but don't stop it.
+type Response struct {
+	tb      testing.TB
+	request string
+	// Recorder holds the recorded response.
+	Recorder *httptest.ResponseRecorder
+}
+
+// Status asserts the status code of the response.
+func (r *Response) Status(code int) *Response {
+	r.tb.Helper()
+	if r.Recorder.Code != code {
+		r.tb.Errorf("%s: expected status %d, got %d", r.request, code, r.Recorder.Code)
+	}
+	return r
+}
+
+// Header asserts the value of a response header.
+func (r *Response) Header(key, value string) *Response {
+	r.tb.Helper()
+	if actual := r.Recorder.Header().Get(key); actual != value {
+		r.tb.Errorf("%s: expected header %s to be %q, got %q", r.request, key, value, actual)
+	}
+	return r
+}
+
+// Body asserts the response body.
+func (r *Response) Body(body string) *Response {
+	r.tb.Helper()
+	if actual := r.Recorder.Body.String(); actual != body {
+		r.tb.Errorf("%s: expected body %q, got %q", r.request, body, actual)
+	}
+	return r
+}
+
+// JSON asserts that the response body is semantically equal to the given
+// JSON document.
+func (r *Response) JSON(expected string) *Response {
+	r.tb.Helper()
+
+	var want any
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		r.tb.Errorf("%s: invalid expected JSON: %v", r.request, err)
+		return r
+	}
+
+	got, ok := r.decode()
+	if ok && !reflect.DeepEqual(want, got) {
+		r.tb.Errorf("%s: expected body %s, got %s", r.request, expected, r.Recorder.Body.String())
+	}
+	return r
+}
+
+// JSONPath asserts the value at a path in the JSON response body. Paths have
+// the form "$.user.emails[0]". The expected value is compared to the
+// decoded value after a round trip through encoding/json, so numbers of any
+// type can be used.
+func (r *Response) JSONPath(path string, expected any) *Response {
+	r.tb.Helper()
+
+	doc, ok := r.decode()
+	if !ok {
+		return r
+	}
+
+	got, err := lookup(doc, path)
+	if err != nil {
+		r.tb.Errorf("%s: %s: %v", r.request, path, err)
+		return r
+	}
+
+	want, err := roundTrip(expected)
+	if err != nil {
+		r.tb.Errorf("%s: %s: invalid expected value: %v", r.request, path, err)
+		return r
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		r.tb.Errorf("%s: %s: expected %v, got %v", r.request, path, want, got)
+	}
+	return r
+}
+
+// ExpectError asserts that the response is an error response sent by
+// [goweb.RespondError] with the given error code.
+func (r *Response) ExpectError(code string) *Response {
+	r.tb.Helper()
+
+	var body struct {
+		Code *string `json:"code"`
+	}
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), &body); err != nil || body.Code == nil {
+		r.tb.Errorf("%s: expected error response, got %s", r.request, r.Recorder.Body.String())
+		return r
+	}
+
+	if *body.Code != code {
+		r.tb.Errorf("%s: expected error code %q, got %q", r.request, code, *body.Code)
+	}
+	return r
+}
+
+// Decode decodes the JSON response body into v.
+func (r *Response) Decode(v any) *Response {
+	r.tb.Helper()
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), v); err != nil {
+		r.tb.Errorf("%s: decode body: %v", r.request, err)
+	}
+	return r
+}
+
+// decode returns the generically decoded JSON response body.
+func (r *Response) decode() (any, bool) {
+	r.tb.Helper()
+
+	var doc any
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), &doc); err != nil {
+		r.tb.Errorf("%s: decode body: %v", r.request, err)
+		return nil, false
+	}
+	return doc, true
+}
+
+// lookup returns the value at the path in a generically decoded JSON document.
+func lookup(doc any, path string) (any, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("path must start with $")
+	}
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			rest = rest[end+1:]
+
+			obj, ok := doc.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s is not an object", key)
+			}
+			if doc, ok = obj[key]; !ok {
+				return nil, fmt.Errorf("key %s not found", key)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index")
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %s", rest[1:end])
+			}
+			rest = rest[end+1:]
+
+			arr, ok := doc.([]any)
+			if !ok {
+				return nil, fmt.Errorf("[%d] is not an array", i)
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, fmt.Errorf("index %d out of range", i)
+			}
+			doc = arr[i]
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest[0])
+		}
+	}
+
+	return doc, nil
+}
+
+// roundTrip converts v into its generically decoded JSON representation.
+func roundTrip(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result any
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
@@ -0,0 +1,150 @@
+// Package routetest provides a fluent in-process client for testing route
+// trees.
+//
+//	rt := routetest.New(t, tree)
+//	rt.GET("/users/1").WithHeader("Accept", "application/json").Expect(t).
+//		Status(http.StatusOK).
+//		JSONPath("$.name", "alice")
+//	rt.GET("/users/missing").Expect(t).ExpectError("not_found")
+package routetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sehrgutesoftware/goweb/route"
+)
+
+// Tester sends requests to a built route tree without a network connection.
+type Tester struct {
+	router *route.Router
+}
+
+// New builds the route tree with the given options. Build errors fail the
+// test immediately.
+func New(tb testing.TB, r *route.Route, opts ...route.Option) *Tester {
+	tb.Helper()
+
+	router, err := r.Build(opts...)
+	if err != nil {
+		tb.Fatalf("build route tree: %v", err)
+	}
+
+	return &Tester{router: router}
+}
+
+// Router returns the router built from the route tree.
+func (t *Tester) Router() *route.Router {
+	return t.router
+}
+
+// Request creates a request with the given method and path.
+func (t *Tester) Request(method, path string) *Request {
+	return &Request{
+		tester: t,
+		method: method,
+		path:   path,
+		header: make(http.Header),
+	}
+}
+
+// GET creates a GET request for the path.
+func (t *Tester) GET(path string) *Request {
+	return t.Request(http.MethodGet, path)
+}
+
+// HEAD creates a HEAD request for the path.
+func (t *Tester) HEAD(path string) *Request {
+	return t.Request(http.MethodHead, path)
+}
+
+// POST creates a POST request for the path.
+func (t *Tester) POST(path string) *Request {
+	return t.Request(http.MethodPost, path)
+}
+
+// PUT creates a PUT request for the path.
+func (t *Tester) PUT(path string) *Request {
+	return t.Request(http.MethodPut, path)
+}
+
+// PATCH creates a PATCH request for the path.
+func (t *Tester) PATCH(path string) *Request {
+	return t.Request(http.MethodPatch, path)
+}
+
+// DELETE creates a DELETE request for the path.
+func (t *Tester) DELETE(path string) *Request {
+	return t.Request(http.MethodDelete, path)
+}
+
+// Request is a request under construction.
+type Request struct {
+	tester *Tester
+	method string
+	path   string
+	host   string
+	header http.Header
+	body   io.Reader
+	err    error
+}
+
+// WithHeader sets a request header.
+func (r *Request) WithHeader(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// WithHost sets the host of the request.
+func (r *Request) WithHost(host string) *Request {
+	r.host = host
+	return r
+}
+
+// WithBody sets the request body.
+func (r *Request) WithBody(body io.Reader) *Request {
+	r.body = body
+	return r
+}
+
+// WithJSON sets the request body to the JSON encoding of v and the
+// Content-Type header accordingly.
+func (r *Request) WithJSON(v any) *Request {
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+	}
+	r.body = bytes.NewReader(data)
+	r.header.Set("Content-Type", "application/json")
+	return r
+}
+
+// Expect sends the request and returns the response for making assertions.
+func (r *Request) Expect(tb testing.TB) *Response {
+	tb.Helper()
+
+	if r.err != nil {
+		tb.Fatalf("prepare request %s %s: %v", r.method, r.path, r.err)
+	}
+
+	req := httptest.NewRequest(r.method, r.path, r.body)
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if r.host != "" {
+		req.Host = r.host
+	}
+
+	rec := httptest.NewRecorder()
+	r.tester.router.ServeHTTP(rec, req)
+
+	return &Response{
+		tb:       tb,
+		request:  r.method + " " + r.path,
+		Recorder: rec,
+	}
+}
@@ -0,0 +1,39 @@
+package routetest_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sehrgutesoftware/goweb"
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/sehrgutesoftware/goweb/route/routetest"
+)
+
+func TestItTestsRouteTrees(t *testing.T) {
+	rt := routetest.New(t, route.Group("/users", []*route.Route{
+		route.Func("GET", "/:id", func(w http.ResponseWriter, r *http.Request) {
+			goweb.Respond(w, r, map[string]any{
+				"id":     r.Header.Get("X-User"),
+				"name":   "alice",
+				"emails": []string{"alice@example.com"},
+				"age":    42,
+			})
+		}),
+	}), route.WithJSONErrors())
+
+	rt.GET("/users/1").WithHeader("X-User", "1").Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/json").
+		JSONPath("$.id", "1").
+		JSONPath("$.name", "alice").
+		JSONPath("$.emails[0]", "alice@example.com").
+		JSONPath("$.age", 42)
+
+	rt.DELETE("/users/1").Expect(t).
+		Status(http.StatusMethodNotAllowed).
+		ExpectError("method_not_allowed")
+
+	rt.GET("/posts").Expect(t).
+		Status(http.StatusNotFound).
+		JSON(`{"code":"not_found","message":"not found","detail":null}`)
+}
@@ -0,0 +1,237 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// serveMuxBackend is a [Backend] based on [http.ServeMux].
+type serveMuxBackend struct {
+	mux     *http.ServeMux
+	cfg     BackendConfig
+	methods []string
+}
+
+// NewServeMuxBackend creates a [Backend] based on the pattern matching of
+// [http.ServeMux].
+//
+// Paths are translated to ServeMux patterns: parameters become "{p0}",
+// catch-all parameters "{p1...}" and paths ending with a slash match only
+// themselves. Parameters keep their names in the request context, and
+// static segments are matched literally, even if they contain braces.
+//
+// Like httprouter, the backend answers requests for the wrong method with
+// 405 and an Allow header, and OPTIONS requests with the Allow header.
+// Requests for a path with a superfluous or missing trailing slash are
+// redirected if the other path exists, with 301 for GET requests and 307
+// otherwise. Unlike httprouter, ServeMux prefers static segments over
+// parameters, so paths that conflict in httprouter can be registered.
+func NewServeMuxBackend(cfg BackendConfig) Backend {
+	return &serveMuxBackend{
+		mux: http.NewServeMux(),
+		cfg: cfg,
+	}
+}
+
+// Handle registers the handler for the method and path.
+func (b *serveMuxBackend) Handle(method, path string, handler http.Handler) (err error) {
+	// ServeMux panics on conflicting patterns.
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%v", p)
+		}
+	}()
+
+	pattern, params := muxPattern(path)
+	b.mux.Handle(method+" "+pattern, b.wrap(method, params, handler))
+	if !slices.Contains(b.methods, method) {
+		b.methods = append(b.methods, method)
+	}
+	return nil
+}
+
+// Conflict reports why the two paths conflict in ServeMux. Paths conflict if
+// they only differ in the names of their parameters, or if both match some
+// paths, but neither is more specific than the other.
+func (b *serveMuxBackend) Conflict(x, y string) (reason string) {
+	if x == y {
+		return "duplicate route"
+	}
+	if anonymousParams(x) == anonymousParams(y) {
+		return "wildcard names differ"
+	}
+
+	// ServeMux panics on registering ambiguous patterns.
+	defer func() {
+		if p := recover(); p != nil {
+			reason = "both match some paths, but neither is more specific"
+		}
+	}()
+	mux := http.NewServeMux()
+	for _, path := range []string{x, y} {
+		pattern, _ := muxPattern(path)
+		mux.Handle(pattern, http.NotFoundHandler())
+	}
+	return ""
+}
+
+// ServeHTTP dispatches the request to the handler of the matching pattern.
+func (b *serveMuxBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Requests ServeMux would answer itself, with a 404 or a redirect, are
+	// handled like httprouter does.
+	if h, _ := b.mux.Handler(r); !isMuxHandler(h) {
+		b.unmatched(w, r)
+		return
+	}
+	b.mux.ServeHTTP(w, r)
+}
+
+// muxHandler is a handler registered with the ServeMux of the backend.
+type muxHandler func(http.ResponseWriter, *http.Request)
+
+// ServeHTTP calls the handler.
+func (h muxHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h(w, r)
+}
+
+// isMuxHandler reports whether the handler was registered by the backend.
+func isMuxHandler(h http.Handler) bool {
+	_, ok := h.(muxHandler)
+	return ok
+}
+
+// wrap makes the path values of the request available as httprouter params.
+func (b *serveMuxBackend) wrap(method string, params []muxParam, next http.Handler) http.Handler {
+	return muxHandler(func(w http.ResponseWriter, r *http.Request) {
+		// ServeMux serves HEAD requests with GET handlers, httprouter doesn't.
+		if r.Method != method {
+			b.unmatched(w, r)
+			return
+		}
+
+		if len(params) > 0 {
+			ps := make(httprouter.Params, len(params))
+			for i, p := range params {
+				ps[i] = httprouter.Param{Key: p.name, Value: r.PathValue(p.key)}
+				if p.catchAll {
+					ps[i].Value = "/" + ps[i].Value
+				}
+			}
+			r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, ps))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unmatched responds to a request that has no handler for its method, with a
+// redirect if the path with or without a trailing slash is registered for
+// the method, with 405 if the path is registered for other methods, and 404
+// otherwise.
+func (b *serveMuxBackend) unmatched(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect && r.URL.Path != "/" && b.redirect(w, r) {
+		return
+	}
+
+	var allowed []string
+	for _, method := range b.methods {
+		if method == r.Method || method == http.MethodOptions {
+			continue
+		}
+
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		if _, pattern := b.mux.Handler(probe); strings.HasPrefix(pattern, method+" ") {
+			allowed = append(allowed, method)
+		}
+	}
+
+	if len(allowed) == 0 {
+		if b.cfg.NotFound != nil {
+			b.cfg.NotFound.ServeHTTP(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	allowed = append(allowed, http.MethodOptions)
+	slices.Sort(allowed)
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	if b.cfg.MethodNotAllowed != nil {
+		b.cfg.MethodNotAllowed.ServeHTTP(w, r)
+	} else {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// redirect redirects the request to the path with or without a trailing
+// slash, if that path is registered for the method.
+func (b *serveMuxBackend) redirect(w http.ResponseWriter, r *http.Request) bool {
+	path := r.URL.Path
+	if strings.HasSuffix(path, "/") {
+		path = path[:len(path)-1]
+	} else {
+		path += "/"
+	}
+
+	probe := r.Clone(r.Context())
+	probe.URL.Path = path
+	probe.URL.RawPath = ""
+	if h, pattern := b.mux.Handler(probe); !isMuxHandler(h) || !strings.HasPrefix(pattern, r.Method+" ") {
+		return false
+	}
+
+	code := http.StatusMovedPermanently
+	if r.Method != http.MethodGet {
+		code = http.StatusTemporaryRedirect
+	}
+	http.Redirect(w, r, probe.URL.String(), code)
+	return true
+}
+
+// muxParam is a parameter of a ServeMux pattern.
+type muxParam struct {
+	// key is the name of the wildcard in the pattern.
+	key string
+	// name is the name of the parameter in the httprouter path.
+	name string
+	// catchAll reports whether the parameter is a catch-all parameter.
+	catchAll bool
+}
+
+// muxPattern translates an httprouter path into a ServeMux pattern without
+// method. Wildcards are named by position, as httprouter allows parameter
+// names that are invalid in ServeMux, and static segments are escaped.
+func muxPattern(path string) (string, []muxParam) {
+	var params []muxParam
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case isParam(segment):
+			p := muxParam{key: fmt.Sprintf("p%d", len(params)), name: segment[1:]}
+			params = append(params, p)
+			segments[i] = "{" + p.key + "}"
+		case isCatchAll(segment):
+			p := muxParam{key: fmt.Sprintf("p%d", len(params)), name: segment[1:], catchAll: true}
+			params = append(params, p)
+			segments[i] = "{" + p.key + "...}"
+		case segment == "" && i == len(segments)-1:
+			// A trailing slash matches only the path itself, not the subtree.
+			segments[i] = "{$}"
+		default:
+			segments[i] = url.PathEscape(segment)
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
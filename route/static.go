@@ -0,0 +1,238 @@
+package route
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// staticParam is the name of the catch-all parameter of static routes.
+const staticParam = "filepath"
+
+// StaticOptions configures a [Static] route.
+type StaticOptions struct {
+	// CacheControl is the Cache-Control header of files without a content
+	// hash in their name. Defaults to "no-cache", i.e. clients revalidate
+	// using the ETag.
+	CacheControl string
+	// HashedCacheControl is the Cache-Control header of files with a content
+	// hash in their name. Defaults to "public, max-age=31536000, immutable".
+	HashedCacheControl string
+	// Hashed reports whether a file name contains a content hash. By default,
+	// names with a dot or dash separated part of at least eight alphanumeric
+	// characters including a digit, such as "app.3f9a2c1e.js", are hashed.
+	Hashed func(name string) bool
+	// SPA serves index.html for paths that match no file, so that a single
+	// page application can handle them.
+	SPA bool
+	// JSONNotFound responds with [ErrNotFound] using [goweb.RespondError]
+	// for paths that match no file.
+	JSONNotFound bool
+}
+
+// Static creates a route that serves the files of fsys below the prefix, for
+// GET and HEAD requests.
+//
+// Strong ETags are computed from the file contents when the route is created.
+// If the client accepts gzip encoding, a pre-compressed sibling named like the
+// file with a ".gz" extension is served instead of the file, if present.
+// Requests for a directory are answered with its index.html.
+func Static(prefix string, fsys fs.FS, opts StaticOptions) *Route {
+	h, err := newStaticHandler(fsys, opts)
+	return &Route{
+		methods: []string{http.MethodGet, http.MethodHead},
+		path:    strings.TrimSuffix(prefix, "/") + "/*" + staticParam,
+		handler: h,
+		err:     err,
+	}
+}
+
+// staticFile is a file served by a [Static] route.
+type staticFile struct {
+	etag string
+	// gzip is the pre-compressed sibling of the file, if any.
+	gzip *staticFile
+	name string
+}
+
+// staticHandler is the handler of a [Static] route.
+type staticHandler struct {
+	fsys  fs.FS
+	opts  StaticOptions
+	files map[string]*staticFile
+}
+
+// newStaticHandler creates the handler, computing the ETags of all files.
+func newStaticHandler(fsys fs.FS, opts StaticOptions) (*staticHandler, error) {
+	if opts.CacheControl == "" {
+		opts.CacheControl = "no-cache"
+	}
+	if opts.HashedCacheControl == "" {
+		opts.HashedCacheControl = "public, max-age=31536000, immutable"
+	}
+	if opts.Hashed == nil {
+		opts.Hashed = hashedName
+	}
+
+	h := staticHandler{
+		fsys:  fsys,
+		opts:  opts,
+		files: make(map[string]*staticFile),
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		h.files[name] = &staticFile{
+			name: name,
+			etag: `"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, file := range h.files {
+		if original, ok := h.files[strings.TrimSuffix(name, ".gz")]; ok && original != file {
+			original.gzip = file
+		}
+	}
+
+	return &h, nil
+}
+
+// ServeHTTP serves the requested file.
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	file := h.lookup(httprouter.ParamsFromContext(r.Context()).ByName(staticParam))
+	if file == nil {
+		if h.opts.JSONNotFound {
+			_ = goweb.RespondError(w, r, ErrNotFound)
+		} else {
+			http.NotFound(w, r)
+		}
+		return
+	}
+
+	if ct := mime.TypeByExtension(path.Ext(file.name)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+
+	if h.opts.Hashed(path.Base(file.name)) {
+		w.Header().Set("Cache-Control", h.opts.HashedCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", h.opts.CacheControl)
+	}
+
+	if file.gzip != nil {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if acceptsGzip(r) {
+			w.Header().Set("Content-Encoding", "gzip")
+			file = file.gzip
+		}
+	}
+
+	w.Header().Set("ETag", file.etag)
+
+	content, err := h.open(file.name)
+	if err != nil {
+		_ = goweb.RespondError(w, r, err)
+		return
+	}
+	defer content.Close()
+
+	// The modification time is left out on purpose, embedded files have none
+	// and the ETag is the better validator anyway.
+	http.ServeContent(w, r, file.name, time.Time{}, content)
+}
+
+// open opens the named file for reading and seeking.
+func (h *staticHandler) open(name string) (io.ReadSeekCloser, error) {
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if rsc, ok := f.(io.ReadSeekCloser); ok {
+		return rsc, nil
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return nopCloser{bytes.NewReader(content)}, nil
+}
+
+// nopCloser adds a no-op Close method to an [io.ReadSeeker].
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+// Close does nothing.
+func (nopCloser) Close() error {
+	return nil
+}
+
+// lookup returns the file for the request path, or nil if there is none.
+func (h *staticHandler) lookup(p string) *staticFile {
+	name := strings.TrimPrefix(path.Clean("/"+p), "/")
+
+	candidates := []string{name, path.Join(name, "index.html")}
+	if h.opts.SPA {
+		candidates = append(candidates, "index.html")
+	}
+
+	for _, candidate := range candidates {
+		if file, ok := h.files[candidate]; ok {
+			return file
+		}
+	}
+	return nil
+}
+
+// hashPattern matches file names with a content hash.
+var hashPattern = regexp.MustCompile(`[.-]([0-9A-Za-z_]{8,})\.[^.]+$`)
+
+// hashedName reports whether the file name contains a content hash.
+func hashedName(name string) bool {
+	m := hashPattern.FindStringSubmatch(name)
+	return m != nil && strings.ContainsAny(m[1], "0123456789")
+}
+
+// acceptsGzip reports whether the client accepts gzip encoded responses.
+func acceptsGzip(r *http.Request) bool {
+	for coding := range strings.SplitSeq(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		name = strings.TrimSpace(name)
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package route_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItServesStaticFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":                {Data: []byte("<html></html>")},
+		"assets/app.3f9a2c1e.js":    {Data: []byte("console.log(1)")},
+		"assets/app.3f9a2c1e.js.gz": {Data: []byte("gzipped")},
+	}
+
+	router, err := route.Group("/", []*route.Route{
+		route.Static("/app", fsys, route.StaticOptions{SPA: true}),
+		route.Static("/api/docs", fsys, route.StaticOptions{JSONNotFound: true}),
+	}).Build()
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/app/assets/app.3f9a2c1e.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "console.log(1)", w.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "javascript")
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// Pre-compressed siblings are served to clients accepting gzip
+	r := httptest.NewRequest("GET", "/app/assets/app.3f9a2c1e.js", nil)
+	r.Header.Set("Accept-Encoding", "br, gzip")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, "gzipped", w.Body.String())
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	// ETags are revalidated
+	r = httptest.NewRequest("GET", "/app/assets/app.3f9a2c1e.js", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// Unknown paths fall back to the index of the single page application
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/app/users/42", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "<html></html>", w.Body.String())
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	// Misses below API-style prefixes are JSON errors
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/docs/missing.json", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"code":"not_found","message":"not found","detail":null}`, w.Body.String())
+}
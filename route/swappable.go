@@ -0,0 +1,57 @@
+package route
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// Swappable is an [http.Handler] serving a route tree that can be replaced at
+// runtime.
+//
+// Swapping is atomic: each request is served completely by the router that
+// was current when it arrived, in-flight requests are not affected.
+//
+// The zero value is ready to use, without options. Until the first successful
+// [Swappable.Swap], it answers requests with [ErrUnavailable].
+type Swappable struct {
+	router atomic.Pointer[Router]
+	opts   []Option
+}
+
+// NewSwappable builds the route tree with the given options and returns a
+// handler serving it. The options are reused for every [Swappable.Swap].
+func NewSwappable(r *Route, opts ...Option) (*Swappable, error) {
+	s := Swappable{opts: opts}
+	if err := s.Swap(r); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Swap builds the route tree and replaces the current router with it. If the
+// build fails, the current router is kept and the error is returned.
+func (s *Swappable) Swap(r *Route) error {
+	router, err := r.Build(s.opts...)
+	if err != nil {
+		return err
+	}
+	s.router.Store(router)
+	return nil
+}
+
+// Router returns the current router, or nil if there is none yet.
+func (s *Swappable) Router() *Router {
+	return s.router.Load()
+}
+
+// ServeHTTP serves the request with the current router.
+func (s *Swappable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router := s.router.Load()
+	if router == nil {
+		_ = goweb.RespondError(w, r, ErrUnavailable)
+		return
+	}
+	router.ServeHTTP(w, r)
+}
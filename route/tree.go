@@ -0,0 +1,431 @@
+package route
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// treeBackend is a [Backend] based on a radix tree per method.
+type treeBackend struct {
+	trees map[string]*tree
+	cfg   BackendConfig
+}
+
+// tree is the radix tree of the routes of one method.
+type tree struct {
+	root node
+}
+
+// node is a node of a radix tree. Static nodes match their prefix, parameter
+// nodes a whole path segment and catch-all nodes the rest of the path,
+// starting with a slash.
+type node struct {
+	// prefix is the static text matched by the node.
+	prefix string
+	// indices are the first bytes of the prefixes of the static children.
+	indices string
+	// static are the children with a static prefix.
+	static []*node
+	// params are the parameter children, constrained ones first.
+	params []*node
+	// catchAll is the catch-all child, if any.
+	catchAll *node
+
+	// name is the name of the parameter matched by the node.
+	name string
+	// constraint is the constraint of the parameter, if any.
+	constraint string
+	// check checks the value of the parameter against the constraint.
+	check func(string) bool
+	// maxParams is the maximum number of parameters of the paths through the
+	// parameter node, counting from the node.
+	maxParams int
+
+	// handler handles the path ending at the node, if any.
+	handler http.Handler
+}
+
+// NewTreeBackend creates a [Backend] based on a radix tree.
+//
+// Unlike httprouter, it allows static, parameter and catch-all segments at
+// the same position. Static segments take precedence over parameters, which
+// take precedence over catch-all parameters. Parameters with a constraint
+// are tried before parameters without one, in the order of registration.
+// If the rest of the path doesn't match, the next candidate is tried, so a
+// request failing the constraint of one route can match another route.
+//
+// Like httprouter, the backend answers requests for the wrong method with 405
+// and an Allow header, and OPTIONS requests with the Allow header. Requests
+// for a path with a superfluous or missing trailing slash are redirected if
+// the other path exists.
+func NewTreeBackend(cfg BackendConfig) Backend {
+	return &treeBackend{
+		trees: make(map[string]*tree),
+		cfg:   cfg,
+	}
+}
+
+// MatchesConstraints reports that the backend matches parameter constraints.
+func (b *treeBackend) MatchesConstraints() bool {
+	return true
+}
+
+// Handle registers the handler for the method and path.
+func (b *treeBackend) Handle(method, path string, handler http.Handler) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path must begin with a slash: %q", path)
+	}
+
+	t := b.trees[method]
+	if t == nil {
+		t = &tree{}
+		b.trees[method] = t
+	}
+
+	n, err := t.root.insert(path)
+	if err != nil {
+		return err
+	}
+	if n.handler != nil {
+		return errors.New("duplicate route")
+	}
+	n.handler = handler
+
+	return nil
+}
+
+// Conflict reports why the two paths conflict in the tree. Paths conflict if
+// they only differ in the names of their parameters, or if they have
+// different catch-all parameters at the same position.
+func (b *treeBackend) Conflict(x, y string) string {
+	if x == y {
+		return "duplicate route"
+	}
+
+	cx, cy := canonicalPath(x), canonicalPath(y)
+	if cx == cy {
+		return "wildcard names differ"
+	}
+
+	px, ax, okx := strings.Cut(x, "*")
+	py, ay, oky := strings.Cut(y, "*")
+	if okx && oky && px == py && ax != ay {
+		return "catch-all parameters differ"
+	}
+	return ""
+}
+
+// ServeHTTP dispatches the request to the handler of the matching route.
+func (b *treeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	if t := b.trees[r.Method]; t != nil {
+		var params httprouter.Params
+		if n := t.root.lookup(path, &params); n != nil {
+			if len(params) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, params))
+			}
+			n.handler.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method != http.MethodConnect && path != "/" && t.redirect(w, r) {
+			return
+		}
+	}
+
+	if allow := b.allowed(path, r.Method); allow != "" {
+		w.Header().Set("Allow", allow)
+		switch {
+		case r.Method == http.MethodOptions:
+		case b.cfg.MethodNotAllowed != nil:
+			b.cfg.MethodNotAllowed.ServeHTTP(w, r)
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	if b.cfg.NotFound != nil {
+		b.cfg.NotFound.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// redirect redirects the request to the path with or without a trailing
+// slash, if that path exists.
+func (t *tree) redirect(w http.ResponseWriter, r *http.Request) bool {
+	path := r.URL.Path
+	if strings.HasSuffix(path, "/") {
+		path = path[:len(path)-1]
+	} else {
+		path += "/"
+	}
+
+	var params httprouter.Params
+	if t.root.lookup(path, &params) == nil {
+		return false
+	}
+
+	code := http.StatusMovedPermanently
+	if r.Method != http.MethodGet {
+		code = http.StatusTemporaryRedirect
+	}
+	u := *r.URL
+	u.Path = path
+	u.RawPath = ""
+	http.Redirect(w, r, u.String(), code)
+	return true
+}
+
+// allowed returns the sorted, comma separated list of methods the path is
+// served with, including OPTIONS, or an empty string if it isn't served.
+func (b *treeBackend) allowed(path, method string) string {
+	var allow []string
+	for m, t := range b.trees {
+		if m == method || m == http.MethodOptions {
+			continue
+		}
+		var params httprouter.Params
+		if t.root.lookup(path, &params) != nil {
+			allow = append(allow, m)
+		}
+	}
+	if len(allow) == 0 {
+		return ""
+	}
+
+	allow = append(allow, http.MethodOptions)
+	slices.Sort(allow)
+	return strings.Join(allow, ", ")
+}
+
+// insert adds the nodes of the path below n. It returns the node the path
+// ends at.
+func (n *node) insert(path string) (*node, error) {
+	var static strings.Builder
+	var params []*node
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if i > 0 {
+			static.WriteByte('/')
+		}
+
+		switch {
+		case isParam(segment):
+			name, constraint := splitParam(segment)
+			if name == "" {
+				return nil, fmt.Errorf("parameter without a name in %q", path)
+			}
+			child, err := n.insertStatic(static.String()).param(name, constraint)
+			if err != nil {
+				return nil, err
+			}
+			n = child
+			static.Reset()
+			params = append(params, child)
+		case isCatchAll(segment):
+			if i != len(segments)-1 {
+				return nil, fmt.Errorf("catch-all parameter must be at the end of %q", path)
+			}
+			name, constraint := splitParam(segment)
+			if name == "" {
+				return nil, fmt.Errorf("catch-all parameter without a name in %q", path)
+			}
+			// The catch-all value includes the slash before it.
+			prefix := strings.TrimSuffix(static.String(), "/")
+			child, err := n.insertStatic(prefix).catchAllChild(name, constraint)
+			if err != nil {
+				return nil, err
+			}
+			countParams(append(params, child))
+			return child, nil
+		case strings.ContainsAny(segment, ":*"):
+			return nil, fmt.Errorf("parameters must span a whole path segment in %q", path)
+		default:
+			static.WriteString(segment)
+		}
+	}
+
+	countParams(params)
+	return n.insertStatic(static.String()), nil
+}
+
+// countParams updates the maximum number of parameters of the parameter
+// nodes of a path, so the parameters of a request are allocated at once.
+func countParams(params []*node) {
+	for i, p := range params {
+		p.maxParams = max(p.maxParams, len(params)-i)
+	}
+}
+
+// insertStatic returns the node matching the static text below n, splitting
+// and adding nodes as needed.
+func (n *node) insertStatic(text string) *node {
+	for text != "" {
+		i := strings.IndexByte(n.indices, text[0])
+		if i < 0 {
+			child := &node{prefix: text}
+			n.indices += text[:1]
+			n.static = append(n.static, child)
+			return child
+		}
+
+		child := n.static[i]
+		common := 0
+		for common < min(len(text), len(child.prefix)) && text[common] == child.prefix[common] {
+			common++
+		}
+		if common < len(child.prefix) {
+			// Split the child at the end of the common prefix
+			rest := *child
+			rest.prefix = child.prefix[common:]
+			*child = node{
+				prefix:  child.prefix[:common],
+				indices: rest.prefix[:1],
+				static:  []*node{&rest},
+			}
+		}
+
+		n = child
+		text = text[common:]
+	}
+	return n
+}
+
+// param returns the parameter child of n with the given name and constraint,
+// adding it if needed.
+func (n *node) param(name, constraint string) (*node, error) {
+	for _, child := range n.params {
+		if child.name == name && child.constraint == constraint {
+			return child, nil
+		}
+	}
+
+	child, err := newParamNode(name, constraint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Constrained parameters are tried first.
+	i := len(n.params)
+	if constraint != "" {
+		i = slices.IndexFunc(n.params, func(p *node) bool { return p.constraint == "" })
+		if i < 0 {
+			i = len(n.params)
+		}
+	}
+	n.params = slices.Insert(n.params, i, child)
+
+	return child, nil
+}
+
+// catchAllChild returns the catch-all child of n, adding it if needed.
+func (n *node) catchAllChild(name, constraint string) (*node, error) {
+	if n.catchAll != nil {
+		if n.catchAll.name != name || n.catchAll.constraint != constraint {
+			return nil, fmt.Errorf("catch-all parameter *%s conflicts with *%s", name, n.catchAll.name)
+		}
+		return n.catchAll, nil
+	}
+
+	child, err := newParamNode(name, constraint)
+	if err != nil {
+		return nil, err
+	}
+	n.catchAll = child
+	return child, nil
+}
+
+// newParamNode creates a parameter node.
+func newParamNode(name, constraint string) (*node, error) {
+	n := node{name: name, constraint: constraint}
+	if constraint != "" {
+		check, ok := constraints[constraint]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownConstraint, constraint)
+		}
+		n.check = check
+	}
+	return &n, nil
+}
+
+// lookup returns the node with a handler matching the path below n, whose
+// prefix has been matched already, and appends the parameters of the path
+// to params. It returns nil if no route matches.
+func (n *node) lookup(path string, params *httprouter.Params) *node {
+	if path == "" {
+		if n.handler != nil {
+			return n
+		}
+		return nil
+	}
+
+	if i := strings.IndexByte(n.indices, path[0]); i >= 0 {
+		child := n.static[i]
+		if strings.HasPrefix(path, child.prefix) {
+			if found := child.lookup(path[len(child.prefix):], params); found != nil {
+				return found
+			}
+		}
+	}
+
+	if len(n.params) > 0 {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end > 0 {
+			value := path[:end]
+			for _, child := range n.params {
+				if child.check != nil && !child.check(value) {
+					continue
+				}
+				if *params == nil {
+					*params = make(httprouter.Params, 0, child.maxParams)
+				}
+				*params = append(*params, httprouter.Param{Key: child.name, Value: value})
+				if found := child.lookup(path[end:], params); found != nil {
+					return found
+				}
+				*params = (*params)[:len(*params)-1]
+			}
+		}
+	}
+
+	if child := n.catchAll; child != nil && path[0] == '/' && child.handler != nil {
+		if child.check == nil || child.check(path[1:]) {
+			if *params == nil {
+				*params = make(httprouter.Params, 0, child.maxParams)
+			}
+			*params = append(*params, httprouter.Param{Key: child.name, Value: path})
+			return child
+		}
+	}
+
+	return nil
+}
+
+// canonicalPath replaces the parameter names of a path with placeholders,
+// keeping their constraints.
+func canonicalPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isParam(segment) || isCatchAll(segment) {
+			_, constraint := splitParam(segment)
+			segments[i] = segment[:1]
+			if constraint != "" {
+				segments[i] += "|" + constraint
+			}
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,159 @@
+package route
+
+import (
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sehrgutesoftware/goweb"
+)
+
+// ErrUnsupportedVersion is sent when a client requests an API version that a
+// negotiated route does not serve.
+var ErrUnsupportedVersion = goweb.NewError("unsupported_version", "unsupported API version", http.StatusNotAcceptable)
+
+// VersionOptions configures the version negotiation of [Version] groups.
+//
+// The requested version is taken from the Header, if set and present in the
+// request, or from the "version" parameter of the media types in the Accept
+// header, e.g. "application/vnd.example+json;version=2". Versions are compared
+// without a leading "v", so "2" selects the group "v2".
+type VersionOptions struct {
+	// Header is the name of a request header that carries the version.
+	Header string
+	// Default is the version served to clients that request none. If empty,
+	// the version declared last is served.
+	Default string
+}
+
+// Version creates a route group for an API version. The name of the version
+// is used as path prefix, e.g. "v2" for "/v2/...".
+//
+// With [WithVersionNegotiation], the routes are also available without the
+// prefix, selected by the version the client requests.
+func Version(name string, children []*Route) *Route {
+	return &Route{
+		path:     name,
+		version:  name,
+		children: children,
+	}
+}
+
+// Deprecated marks the route and its children as deprecated. Responses carry
+// a Deprecation header and, if sunset is not zero, a Sunset header (RFC 8594)
+// announcing when the route will be removed.
+func (r *Route) Deprecated(sunset time.Time) *Route {
+	r.deprecated = true
+	r.sunset = sunset
+	return r
+}
+
+// versionRoutes adds a negotiated route without version prefix for every
+// path and method served by one or more versions.
+func versionRoutes(routes []Info) []Info {
+	type location struct{ method, host, path string }
+
+	var locations []location
+	versions := make(map[location][]Info)
+	for _, info := range routes {
+		if info.versionPath == "" {
+			continue
+		}
+
+		loc := location{info.Method, info.Host, unversionedPath(info)}
+		if _, ok := versions[loc]; !ok {
+			locations = append(locations, loc)
+		}
+		versions[loc] = append(versions[loc], info)
+	}
+
+	for _, loc := range locations {
+		routes = append(routes, Info{
+			Method:   loc.method,
+			Path:     loc.path,
+			Host:     loc.host,
+			Params:   paramNames(loc.path),
+			versions: versions[loc],
+		})
+	}
+
+	return routes
+}
+
+// unversionedPath returns the path of a versioned route without the prefix of
+// its version group.
+func unversionedPath(info Info) string {
+	return joinPath(path.Dir(info.versionPath), strings.TrimPrefix(info.Path, info.versionPath))
+}
+
+// negotiate returns a handler that dispatches to the route of the version
+// requested by the client.
+func (r *Router) negotiate(versions []Info) (http.Handler, error) {
+	handlers := make(map[string]http.Handler, len(versions))
+	for _, info := range versions {
+		handler, err := r.compose(info)
+		if err != nil {
+			return nil, err
+		}
+		handlers[normalizeVersion(info.Version)] = handler
+	}
+
+	def := normalizeVersion(r.opts.versioning.Default)
+	if def == "" {
+		def = normalizeVersion(versions[len(versions)-1].Version)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		version := requestedVersion(req, r.opts.versioning.Header)
+		if version == "" {
+			version = def
+		}
+
+		handler, ok := handlers[version]
+		if !ok {
+			_ = goweb.RespondError(w, req, ErrUnsupportedVersion.Apply(map[string]any{"version": version}))
+			return
+		}
+		handler.ServeHTTP(w, req)
+	}), nil
+}
+
+// requestedVersion returns the normalized version requested by the client,
+// or an empty string if it requests none.
+func requestedVersion(r *http.Request, header string) string {
+	if header != "" {
+		if v := r.Header.Get(header); v != "" {
+			return normalizeVersion(v)
+		}
+	}
+
+	for accept := range strings.SplitSeq(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(accept)
+		if err != nil {
+			continue
+		}
+		if v := params["version"]; v != "" {
+			return normalizeVersion(v)
+		}
+	}
+
+	return ""
+}
+
+// normalizeVersion strips the leading "v" of a version.
+func normalizeVersion(v string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "v")
+}
+
+// withDeprecation adds the Deprecation and Sunset headers to responses.
+func withDeprecation(sunset time.Time, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		if !sunset.IsZero() {
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
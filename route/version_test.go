@@ -0,0 +1,62 @@
+package route_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sehrgutesoftware/goweb/route"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItNegotiatesAPIVersions(t *testing.T) {
+	respond := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(body)) }
+	}
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tree := route.Group("/api", []*route.Route{
+		route.Version("v1", []*route.Route{
+			route.Func("GET", "/users", respond("v1")),
+		}).Deprecated(sunset),
+		route.Version("v2", []*route.Route{
+			route.Func("GET", "/users", respond("v2")),
+		}),
+	})
+
+	assert.Equal(t, []string{
+		"GET /api/v1/users version=v1 deprecated sunset=2027-01-01",
+		"GET /api/v2/users version=v2",
+	}, tree.Dump())
+
+	router, err := tree.Build(route.WithVersionNegotiation(route.VersionOptions{Header: "X-API-Version"}))
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		path, header, accept string
+		status               int
+		body                 string
+	}{
+		{"/api/v1/users", "", "", 200, "v1"},
+		{"/api/v2/users", "", "", 200, "v2"},
+		{"/api/users", "", "", 200, "v2"},
+		{"/api/users", "1", "", 200, "v1"},
+		{"/api/users", "", "application/vnd.example+json;version=1", 200, "v1"},
+		{"/api/users", "3", "", 406, ""},
+	} {
+		r := httptest.NewRequest("GET", tc.path, nil)
+		r.Header.Set("X-API-Version", tc.header)
+		r.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, tc.status, w.Code, tc)
+		if tc.body != "" {
+			assert.Equal(t, tc.body, w.Body.String(), tc)
+		}
+		if tc.body == "v1" {
+			assert.Equal(t, "true", w.Header().Get("Deprecation"))
+			assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		}
+	}
+}